@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
 	"strings"
 
 	token "github.com/ZooeyLang/Token"
@@ -10,6 +11,8 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position // Onde o nó começa no código fonte
+	End() token.Position // Logo depois do último caracter do nó
 }
 
 type Statement interface {
@@ -27,9 +30,10 @@ type Program struct {
 }
 
 type OwOStatement struct {
-	Token token.Token
-	Name  *Identifier // Nome da variavel
-	Value Expression  // Expressão que a variavel está recebendo
+	Token   token.Token
+	Name    *Identifier // Nome da variavel
+	Pattern Pattern     // Usado no lugar de Name em owo [a, b] :=: valor e owo q, r :=: valor
+	Value   Expression  // Expressão que a variavel está recebendo
 }
 
 type ReturnStatement struct {
@@ -38,8 +42,14 @@ type ReturnStatement struct {
 }
 
 type Identifier struct {
-	Token token.Token
-	Value string
+	Token   token.Token
+	Value   string
+	Binding *Binding // Preenchido pelo resolver; nil quando a variável é buscada dinamicamente
+}
+
+// Binding liga o uso de um identificador ao escopo em que ele foi declarado
+type Binding struct {
+	Depth int // Quantos escopos acima do uso a variável foi declarada
 }
 
 type ExpressionStatement struct {
@@ -72,27 +82,45 @@ type IfExpression struct {
 	Alternative *BlockStatement
 }
 
+// Ex: notas |> filtrar(aprovado) |> media. Quando Right é uma chamada, Left entra como o seu
+// primeiro argumento; senão Right é a função, chamada só com Left
 type OwOExpression struct {
-	Token       token.Token
-	Expressions []Expression
+	Token token.Token // O token '|>'
+	Left  Expression
+	Right Expression
 }
 
 type BlockStatement struct {
 	Token      token.Token // { token
 	Statements []Statement
+	Rbrace     token.Token // } token
 }
 
 type FunctionLiteral struct {
 	Token      token.Token
 	FnName     string
-	Parameters []*Identifier
+	Parameters []Pattern // Um nome ou um padrão, como em fn(x, [a, b], y = 10, ...resto)
 	Body       *BlockStatement
 }
 
 type CallExpression struct {
 	Token     token.Token
 	Function  Expression
-	Arguments []Expression
+	Arguments []Expression // Os argumentos nomeados, *NamedArgument, vêm depois dos posicionais
+	Rparen    token.Token
+}
+
+// Ex: ...xs em f(...xs), [0, ...xs] e {...padrao, "k": v}, que coloca ali cada elemento ou par de xs
+type SpreadExpression struct {
+	Token token.Token // O token '...'
+	Value Expression
+}
+
+// Ex: y: 2 em f(x, y: 2), que passa 2 para o parâmetro y
+type NamedArgument struct {
+	Token token.Token // O token ':'
+	Name  *Identifier
+	Value Expression
 }
 
 type StringLiteral struct {
@@ -100,16 +128,55 @@ type StringLiteral struct {
 	Value string
 }
 
+// Ex: "média: ${media}". Parts alterna entre os trechos de texto, como StringLiteral, e as expressões
+type TemplateLiteral struct {
+	Token token.Token // O TEMPLATE_HEAD
+	Parts []Expression
+	Tail  token.Token
+}
+
 type IndexExpression struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	Rbracket token.Token
+}
+
+type BreakStatement struct {
+	Token token.Token // O token 'break'
+	Label *Identifier // Laço que deve ser interrompido, nil para o mais interno
 }
 
+type ContinueStatement struct {
+	Token token.Token // O token 'continue'
+	Label *Identifier // Laço que deve seguir para a próxima volta, nil para o mais interno
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BreakStatement) String() string {
+	if bs.Label != nil {
+		return bs.TokenLiteral() + " " + bs.Label.String() + ";"
+	}
+	return bs.TokenLiteral() + ";"
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ContinueStatement) String() string {
+	if cs.Label != nil {
+		return cs.TokenLiteral() + " " + cs.Label.String() + ";"
+	}
+	return cs.TokenLiteral() + ";"
+}
+
+// BindExpression assigns to an existing variable, array element or hash field:
+// x :=: v, arr[i] += v, pessoa.idade++
 type BindExpression struct {
-	Token token.Token // The := token
-	Left  string
-	Value Expression
+	Token    token.Token // The operator token
+	Left     Expression  // *Identifier, *IndexExpression or *FieldExpression
+	Operator string      // :=:, +=, -=, *=, /=, ++ or --
+	Value    Expression  // nil for ++ and --
 }
 
 func (be *BindExpression) expressionNode() {}
@@ -121,21 +188,78 @@ func (be *BindExpression) TokenLiteral() string { return be.Token.Literal }
 func (be *BindExpression) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(be.Left)
-	out.WriteString(be.TokenLiteral())
+	out.WriteString(be.Left.String())
+
+	if be.Value == nil {
+		out.WriteString(be.Operator)
+		return out.String()
+	}
+
+	out.WriteString(" " + be.Operator + " ")
 	out.WriteString(be.Value.String())
 
 	return out.String()
 }
 
-func (be *OwOExpression) expressionNode() {}
+// FieldExpression represents the dotted access to a hash field, as in pessoa.nome
+type FieldExpression struct {
+	Token token.Token // The '.' token
+	Left  Expression
+	Field *Identifier
+}
 
-// TokenLiteral prints the literal value of the token associated with this node
-func (be *OwOExpression) TokenLiteral() string { return be.Token.Literal }
+// Ex: pessoa?.nome, que dá null quando pessoa é null
+type OptionalFieldExpression struct {
+	Token token.Token // O token '?.'
+	Left  Expression
+	Field *Identifier
+}
 
-// String returns a stringified version of the AST for debugging
+// Ex: lista?[0], que dá null quando lista é null. O índice nem chega a ser avaliado nesse caso
+type OptionalIndexExpression struct {
+	Token    token.Token // O token '?['
+	Left     Expression
+	Index    Expression
+	Rbracket token.Token
+}
+
+// O literal null
+type NullLiteral struct {
+	Token token.Token
+}
+
+func (fe *FieldExpression) expressionNode()      {}
+func (fe *FieldExpression) TokenLiteral() string { return fe.Token.Literal }
+func (fe *FieldExpression) String() string {
+	return fe.Left.String() + "." + fe.Field.String()
+}
+
+func (of *OptionalFieldExpression) expressionNode()      {}
+func (of *OptionalFieldExpression) TokenLiteral() string { return of.Token.Literal }
+func (of *OptionalFieldExpression) String() string {
+	return of.Left.String() + "?." + of.Field.String()
+}
+
+func (oi *OptionalIndexExpression) expressionNode()      {}
+func (oi *OptionalIndexExpression) TokenLiteral() string { return oi.Token.Literal }
+func (oi *OptionalIndexExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(oi.Left.String())
+	out.WriteString("?[")
+	out.WriteString(oi.Index.String())
+	out.WriteString("])")
+	return out.String()
+}
+
+func (nl *NullLiteral) expressionNode()      {}
+func (nl *NullLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NullLiteral) String() string       { return nl.Token.Literal }
+
+func (be *OwOExpression) expressionNode()      {}
+func (be *OwOExpression) TokenLiteral() string { return be.Token.Literal }
 func (be *OwOExpression) String() string {
-	return ""
+	return "(" + be.Left.String() + " |> " + be.Right.String() + ")"
 }
 
 func (ie *IndexExpression) expressionNode()      {}
@@ -154,6 +278,22 @@ func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+func (tl *TemplateLiteral) expressionNode()      {}
+func (tl *TemplateLiteral) TokenLiteral() string { return tl.Token.Literal }
+func (tl *TemplateLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString("\"")
+	for _, part := range tl.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			out.WriteString(text.Value)
+			continue
+		}
+		out.WriteString("${" + part.String() + "}")
+	}
+	out.WriteString("\"")
+	return out.String()
+}
+
 func (ce *CallExpression) expressionNode()      {}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
 func (ce *CallExpression) String() string {
@@ -169,6 +309,16 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string       { return "..." + se.Value.String() }
+
+func (na *NamedArgument) expressionNode()      {}
+func (na *NamedArgument) TokenLiteral() string { return na.Token.Literal }
+func (na *NamedArgument) String() string {
+	return na.Name.String() + ": " + na.Value.String()
+}
+
 func (p *Program) TokenLiteral() string {
 	if len(p.Statements) > 0 {
 		return p.Statements[0].TokenLiteral()
@@ -180,6 +330,7 @@ func (p *Program) TokenLiteral() string {
 type LiteralInteger struct {
 	Token token.Token
 	Value int64
+	Big   *big.Int // Preenchido no lugar de Value quando o literal não cabe em int64
 }
 
 type LiteralFloat struct {
@@ -187,10 +338,21 @@ type LiteralFloat struct {
 	Value float64
 }
 
+// Ex: 12.50d, guardado como Unscaled × 10^-Scale, ou seja 1250 com escala 2
+type LiteralDecimal struct {
+	Token    token.Token
+	Unscaled *big.Int
+	Scale    int
+}
+
 func (lf *LiteralFloat) expressionNode()      {}
 func (lf *LiteralFloat) TokenLiteral() string { return lf.Token.Literal }
 func (lf *LiteralFloat) String() string       { return lf.Token.Literal }
 
+func (ld *LiteralDecimal) expressionNode()      {}
+func (ld *LiteralDecimal) TokenLiteral() string { return ld.Token.Literal }
+func (ld *LiteralDecimal) String() string       { return ld.Token.Literal }
+
 func (mv *OwOStatement) statementNode()       {}
 func (mv *OwOStatement) TokenLiteral() string { return mv.Token.Literal }
 
@@ -252,6 +414,9 @@ func (fl *FunctionLiteral) String() string {
 	}
 
 	out.WriteString(fl.TokenLiteral())
+	if fl.FnName != "" {
+		out.WriteString(" " + fl.FnName)
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
@@ -309,7 +474,11 @@ func (ms *OwOStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ms.TokenLiteral() + " ")
-	out.WriteString(ms.Name.String())
+	if ms.Pattern != nil {
+		out.WriteString(ms.Pattern.String())
+	} else {
+		out.WriteString(ms.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ms.Value != nil {
@@ -348,6 +517,22 @@ func (i *Identifier) String() string { return i.Value }
 type ArrayLiteral struct {
 	Token    token.Token
 	Elements []Expression
+	Rbracket token.Token
+}
+
+// Ex: a, b em return a, b. Sempre tem pelo menos dois elementos
+type TupleLiteral struct {
+	Elements []Expression
+}
+
+func (tl *TupleLiteral) expressionNode()      {}
+func (tl *TupleLiteral) TokenLiteral() string { return tl.Elements[0].TokenLiteral() }
+func (tl *TupleLiteral) String() string {
+	elements := []string{}
+	for _, el := range tl.Elements {
+		elements = append(elements, el.String())
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
 }
 
 func (al *ArrayLiteral) expressionNode()      {}
@@ -368,9 +553,13 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// Os pares ficam na ordem em que foram escritos, já que uma chave repetida substitui a anterior.
+// Num ...espalhamento, Keys[i] é o *SpreadExpression e Values[i] é nil
 type HashLiteral struct {
-	Token token.Token // the '{' token
-	Pairs map[Expression]Expression
+	Token  token.Token // the '{' token
+	Keys   []Expression
+	Values []Expression
+	Rbrace token.Token // the '}' token
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -378,8 +567,12 @@ func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for i, key := range hl.Keys {
+		if hl.Values[i] == nil {
+			pairs = append(pairs, key.String())
+			continue
+		}
+		pairs = append(pairs, key.String()+":"+hl.Values[i].String())
 	}
 
 	out.WriteString("{")
@@ -392,6 +585,7 @@ func (hl *HashLiteral) String() string {
 // and consequence expression
 type WhileExpression struct {
 	Token       token.Token // The 'while' token
+	Label       string      // Optional label used by break/continue, as in `outer: while(...)`
 	Condition   Expression
 	Consequence *BlockStatement
 }
@@ -413,11 +607,13 @@ func (we *WhileExpression) String() string {
 	return out.String()
 }
 
+// ForExpression represents `for(init; condition; post) { }`, where every clause is optional
 type ForExpression struct {
-	Token       token.Token
-	Identifier  Statement
-	Condition   Expression
-	Aggregator  Expression
+	Token       token.Token // The 'for' token
+	Label       string
+	Init        []Statement // Run once, in the loop's own scope
+	Condition   Expression  // nil means loop forever
+	Post        []Statement // Run after the body and after every `continue`
 	Consequence *BlockStatement
 }
 
@@ -430,10 +626,56 @@ func (we *ForExpression) TokenLiteral() string { return we.Token.Literal }
 func (we *ForExpression) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("for")
-	out.WriteString(we.Condition.String())
-	out.WriteString(" ")
+	clause := func(statements []Statement) string {
+		parts := []string{}
+		for _, s := range statements {
+			parts = append(parts, strings.TrimSuffix(s.String(), ";"))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	out.WriteString("for(")
+	out.WriteString(clause(we.Init))
+	out.WriteString("; ")
+	if we.Condition != nil {
+		out.WriteString(we.Condition.String())
+	}
+	out.WriteString("; ")
+	out.WriteString(clause(we.Post))
+	out.WriteString(") ")
 	out.WriteString(we.Consequence.String())
 
 	return out.String()
 }
+
+// ForInExpression represents `for value in iterable { }` and `for key, value in iterable { }`
+type ForInExpression struct {
+	Token       token.Token // The 'for' token
+	Label       string
+	Key         *Identifier // Index or key, nil when only one variable is given
+	Value       *Identifier
+	Iterable    Expression
+	Consequence *BlockStatement
+}
+
+func (fi *ForInExpression) expressionNode() {}
+
+// TokenLiteral prints the literal value of the token associated with this node
+func (fi *ForInExpression) TokenLiteral() string { return fi.Token.Literal }
+
+// String returns a stringified version of the AST for debugging
+func (fi *ForInExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for ")
+	if fi.Key != nil {
+		out.WriteString(fi.Key.String() + ", ")
+	}
+	out.WriteString(fi.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fi.Iterable.String())
+	out.WriteString(" ")
+	out.WriteString(fi.Consequence.String())
+
+	return out.String()
+}
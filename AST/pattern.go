@@ -0,0 +1,206 @@
+package ast
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	token "github.com/ZooeyLang/Token"
+)
+
+// Um padrão confere a forma de um valor e pode declarar variáveis com as partes dele
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// Ex: match nota { 10 => "perfeito", 7..9 => "aprovado", _ => "reprovado" }
+type MatchExpression struct {
+	Token   token.Token // O token 'match'
+	Subject Expression
+	Arms    []*MatchArm
+	Rbrace  token.Token
+}
+
+// Ex: n if n > 0 => "positivo". Body é um *BlockStatement quando o braço é escrito com { }
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression // nil quando o braço não tem if
+	Body    Node
+}
+
+// O _, que aceita qualquer valor sem declarar nada
+type WildcardPattern struct {
+	Token token.Token
+}
+
+// Ex: x, que aceita qualquer valor e o guarda em x
+type BindingPattern struct {
+	Name *Identifier
+}
+
+// Ex: n: int, que só aceita inteiros. Name é nil em _: int
+type TypePattern struct {
+	Token token.Token // O token ':'
+	Name  *Identifier
+	Type  token.Token
+}
+
+// Ex: 42, -1.5, "zooey", true, null
+type LiteralPattern struct {
+	Value Expression
+}
+
+// Ex: 1..9, com os dois extremos incluídos
+type RangePattern struct {
+	Token token.Token // O token '..'
+	Low   Expression
+	High  Expression
+}
+
+// Ex: [primeiro, ...resto]. Elements pode ter um único *RestPattern, em qualquer posição
+type ArrayPattern struct {
+	Token    token.Token // O token '['
+	Elements []Pattern
+	Rbracket token.Token
+}
+
+// Ex: ...resto, que guarda num array os elementos que sobraram. Name é nil em ... e ..._
+type RestPattern struct {
+	Token token.Token // O token '...'
+	Name  *Identifier
+}
+
+// Ex: q, r em owo q, r :=: divmod(7, 2), que só aceita tuplas. Como num array, pode ter um único ...resto
+type TuplePattern struct {
+	Elements []Pattern
+}
+
+// Ex: b = 0 em [a, b = 0]. O valor padrão é usado quando o elemento ou a chave falta ou é null
+type DefaultPattern struct {
+	Token   token.Token // O token '='
+	Pattern Pattern
+	Default Expression
+}
+
+// Ex: {"tipo": t, nome, idade: anos}. {nome} é o mesmo que {"nome": nome}; antes do : vem a chave,
+// que pode ser um nome, uma string ou um inteiro, e depois dele o padrão do valor
+type HashPattern struct {
+	Token  token.Token // O token '{'
+	Keys   []Expression
+	Values []Pattern
+	Rbrace token.Token
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match ")
+	out.WriteString(me.Subject.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) String() string       { return wp.Token.Literal }
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Name.TokenLiteral() }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+func (tp *TypePattern) patternNode()         {}
+func (tp *TypePattern) TokenLiteral() string { return tp.Token.Literal }
+func (tp *TypePattern) String() string {
+	if tp.Name == nil {
+		return "_: " + tp.Type.Literal
+	}
+	return tp.Name.String() + ": " + tp.Type.Literal
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Value.TokenLiteral() }
+func (lp *LiteralPattern) String() string       { return patternLiteral(lp.Value) }
+
+func (rp *RangePattern) patternNode()         {}
+func (rp *RangePattern) TokenLiteral() string { return rp.Token.Literal }
+func (rp *RangePattern) String() string {
+	return patternLiteral(rp.Low) + ".." + patternLiteral(rp.High)
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, element := range ap.Elements {
+		elements = append(elements, element.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+func (rp *RestPattern) patternNode()         {}
+func (rp *RestPattern) TokenLiteral() string { return rp.Token.Literal }
+func (rp *RestPattern) String() string {
+	if rp.Name == nil {
+		return "..."
+	}
+	return "..." + rp.Name.String()
+}
+
+func (tp *TuplePattern) patternNode()         {}
+func (tp *TuplePattern) TokenLiteral() string { return tp.Elements[0].TokenLiteral() }
+func (tp *TuplePattern) String() string {
+	elements := []string{}
+	for _, element := range tp.Elements {
+		elements = append(elements, element.String())
+	}
+	return strings.Join(elements, ", ")
+}
+
+func (dp *DefaultPattern) patternNode()         {}
+func (dp *DefaultPattern) TokenLiteral() string { return dp.Token.Literal }
+func (dp *DefaultPattern) String() string {
+	return dp.Pattern.String() + " = " + dp.Default.String()
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		pairs = append(pairs, patternLiteral(key)+": "+hp.Values[i].String())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Strings aparecem entre aspas, para que "1" e 1 não fiquem iguais
+func patternLiteral(exp Expression) string {
+	if str, ok := exp.(*StringLiteral); ok {
+		return strconv.Quote(str.Value)
+	}
+	return exp.String()
+}
@@ -0,0 +1,305 @@
+package ast
+
+import token "github.com/ZooeyLang/Token"
+
+// Pos e End de cada nó. Um nó começa no seu primeiro token (ou no do filho mais à esquerda)
+// e termina no final do seu último token (ou no do filho mais à direita)
+
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+func (p *Program) End() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[len(p.Statements)-1].End()
+	}
+	return token.Position{}
+}
+
+func (mv *OwOStatement) Pos() token.Position { return mv.Token.Pos }
+func (mv *OwOStatement) End() token.Position {
+	if mv.Value == nil {
+		if mv.Name != nil {
+			return mv.Name.End()
+		}
+		if mv.Pattern != nil {
+			return mv.Pattern.End()
+		}
+		return mv.Token.End
+	}
+	return mv.Value.End()
+}
+
+func (rs *ReturnStatement) Pos() token.Position { return rs.Token.Pos }
+func (rs *ReturnStatement) End() token.Position {
+	if rs.ReturnValue == nil {
+		return rs.Token.End
+	}
+	return rs.ReturnValue.End()
+}
+
+func (es *ExpressionStatement) Pos() token.Position {
+	if es.Expression == nil {
+		return es.Token.Pos
+	}
+	return es.Expression.Pos()
+}
+func (es *ExpressionStatement) End() token.Position {
+	if es.Expression == nil {
+		return es.Token.End
+	}
+	return es.Expression.End()
+}
+
+func (bs *BreakStatement) Pos() token.Position { return bs.Token.Pos }
+func (bs *BreakStatement) End() token.Position {
+	if bs.Label == nil {
+		return bs.Token.End
+	}
+	return bs.Label.End()
+}
+
+func (cs *ContinueStatement) Pos() token.Position { return cs.Token.Pos }
+func (cs *ContinueStatement) End() token.Position {
+	if cs.Label == nil {
+		return cs.Token.End
+	}
+	return cs.Label.End()
+}
+
+func (bs *BlockStatement) Pos() token.Position { return bs.Token.Pos }
+func (bs *BlockStatement) End() token.Position { return bs.Rbrace.End }
+
+func (i *Identifier) Pos() token.Position { return i.Token.Pos }
+func (i *Identifier) End() token.Position { return i.Token.End }
+
+func (il *LiteralInteger) Pos() token.Position { return il.Token.Pos }
+func (il *LiteralInteger) End() token.Position { return il.Token.End }
+
+func (lf *LiteralFloat) Pos() token.Position { return lf.Token.Pos }
+func (lf *LiteralFloat) End() token.Position { return lf.Token.End }
+
+func (ld *LiteralDecimal) Pos() token.Position { return ld.Token.Pos }
+func (ld *LiteralDecimal) End() token.Position { return ld.Token.End }
+
+func (sl *StringLiteral) Pos() token.Position { return sl.Token.Pos }
+func (sl *StringLiteral) End() token.Position { return sl.Token.End }
+
+func (tl *TemplateLiteral) Pos() token.Position { return tl.Token.Pos }
+func (tl *TemplateLiteral) End() token.Position { return tl.Tail.End }
+
+func (b *Boolean) Pos() token.Position { return b.Token.Pos }
+func (b *Boolean) End() token.Position { return b.Token.End }
+
+func (pe *PrefixExpression) Pos() token.Position { return pe.Token.Pos }
+func (pe *PrefixExpression) End() token.Position {
+	if pe.Right == nil {
+		return pe.Token.End
+	}
+	return pe.Right.End()
+}
+
+func (oe *InfixExpression) Pos() token.Position {
+	if oe.Left == nil {
+		return oe.Token.Pos
+	}
+	return oe.Left.Pos()
+}
+func (oe *InfixExpression) End() token.Position {
+	if oe.Right == nil {
+		return oe.Token.End
+	}
+	return oe.Right.End()
+}
+
+func (be *BindExpression) Pos() token.Position {
+	if be.Left == nil {
+		return be.Token.Pos
+	}
+	return be.Left.Pos()
+}
+func (be *BindExpression) End() token.Position {
+	if be.Value == nil {
+		return be.Token.End
+	}
+	return be.Value.End()
+}
+
+func (fe *FieldExpression) Pos() token.Position {
+	if fe.Left == nil {
+		return fe.Token.Pos
+	}
+	return fe.Left.Pos()
+}
+func (fe *FieldExpression) End() token.Position {
+	if fe.Field == nil {
+		return fe.Token.End
+	}
+	return fe.Field.End()
+}
+
+func (of *OptionalFieldExpression) Pos() token.Position {
+	if of.Left == nil {
+		return of.Token.Pos
+	}
+	return of.Left.Pos()
+}
+func (of *OptionalFieldExpression) End() token.Position {
+	if of.Field == nil {
+		return of.Token.End
+	}
+	return of.Field.End()
+}
+
+func (oi *OptionalIndexExpression) Pos() token.Position {
+	if oi.Left == nil {
+		return oi.Token.Pos
+	}
+	return oi.Left.Pos()
+}
+func (oi *OptionalIndexExpression) End() token.Position { return oi.Rbracket.End }
+
+func (nl *NullLiteral) Pos() token.Position { return nl.Token.Pos }
+func (nl *NullLiteral) End() token.Position { return nl.Token.End }
+
+func (ie *IfExpression) Pos() token.Position { return ie.Token.Pos }
+func (ie *IfExpression) End() token.Position {
+	if ie.Alternative != nil {
+		return ie.Alternative.End()
+	}
+	if ie.Consequence != nil {
+		return ie.Consequence.End()
+	}
+	return ie.Token.End
+}
+
+func (be *OwOExpression) Pos() token.Position {
+	if be.Left == nil {
+		return be.Token.Pos
+	}
+	return be.Left.Pos()
+}
+func (be *OwOExpression) End() token.Position {
+	if be.Right == nil {
+		return be.Token.End
+	}
+	return be.Right.End()
+}
+
+func (fl *FunctionLiteral) Pos() token.Position { return fl.Token.Pos }
+func (fl *FunctionLiteral) End() token.Position {
+	if fl.Body == nil {
+		return fl.Token.End
+	}
+	return fl.Body.End()
+}
+
+func (ce *CallExpression) Pos() token.Position {
+	if ce.Function == nil {
+		return ce.Token.Pos
+	}
+	return ce.Function.Pos()
+}
+func (ce *CallExpression) End() token.Position { return ce.Rparen.End }
+
+func (se *SpreadExpression) Pos() token.Position { return se.Token.Pos }
+func (se *SpreadExpression) End() token.Position {
+	if se.Value == nil {
+		return se.Token.End
+	}
+	return se.Value.End()
+}
+
+func (na *NamedArgument) Pos() token.Position { return na.Name.Pos() }
+func (na *NamedArgument) End() token.Position {
+	if na.Value == nil {
+		return na.Token.End
+	}
+	return na.Value.End()
+}
+
+func (ie *IndexExpression) Pos() token.Position {
+	if ie.Left == nil {
+		return ie.Token.Pos
+	}
+	return ie.Left.Pos()
+}
+func (ie *IndexExpression) End() token.Position { return ie.Rbracket.End }
+
+func (al *ArrayLiteral) Pos() token.Position { return al.Token.Pos }
+func (al *ArrayLiteral) End() token.Position { return al.Rbracket.End }
+
+func (tl *TupleLiteral) Pos() token.Position { return tl.Elements[0].Pos() }
+func (tl *TupleLiteral) End() token.Position { return tl.Elements[len(tl.Elements)-1].End() }
+
+func (hl *HashLiteral) Pos() token.Position { return hl.Token.Pos }
+func (hl *HashLiteral) End() token.Position { return hl.Rbrace.End }
+
+func (we *WhileExpression) Pos() token.Position { return we.Token.Pos }
+func (we *WhileExpression) End() token.Position {
+	if we.Consequence == nil {
+		return we.Token.End
+	}
+	return we.Consequence.End()
+}
+
+func (we *ForExpression) Pos() token.Position { return we.Token.Pos }
+func (we *ForExpression) End() token.Position {
+	if we.Consequence == nil {
+		return we.Token.End
+	}
+	return we.Consequence.End()
+}
+
+func (fi *ForInExpression) Pos() token.Position { return fi.Token.Pos }
+func (fi *ForInExpression) End() token.Position {
+	if fi.Consequence == nil {
+		return fi.Token.End
+	}
+	return fi.Consequence.End()
+}
+
+func (me *MatchExpression) Pos() token.Position { return me.Token.Pos }
+func (me *MatchExpression) End() token.Position { return me.Rbrace.End }
+
+func (wp *WildcardPattern) Pos() token.Position { return wp.Token.Pos }
+func (wp *WildcardPattern) End() token.Position { return wp.Token.End }
+
+func (bp *BindingPattern) Pos() token.Position { return bp.Name.Pos() }
+func (bp *BindingPattern) End() token.Position { return bp.Name.End() }
+
+func (tp *TypePattern) Pos() token.Position {
+	if tp.Name == nil {
+		return tp.Token.Pos
+	}
+	return tp.Name.Pos()
+}
+func (tp *TypePattern) End() token.Position { return tp.Type.End }
+
+func (lp *LiteralPattern) Pos() token.Position { return lp.Value.Pos() }
+func (lp *LiteralPattern) End() token.Position { return lp.Value.End() }
+
+func (rp *RangePattern) Pos() token.Position { return rp.Low.Pos() }
+func (rp *RangePattern) End() token.Position { return rp.High.End() }
+
+func (ap *ArrayPattern) Pos() token.Position { return ap.Token.Pos }
+func (ap *ArrayPattern) End() token.Position { return ap.Rbracket.End }
+
+func (rp *RestPattern) Pos() token.Position { return rp.Token.Pos }
+func (rp *RestPattern) End() token.Position {
+	if rp.Name == nil {
+		return rp.Token.End
+	}
+	return rp.Name.End()
+}
+
+func (tp *TuplePattern) Pos() token.Position { return tp.Elements[0].Pos() }
+func (tp *TuplePattern) End() token.Position { return tp.Elements[len(tp.Elements)-1].End() }
+
+func (dp *DefaultPattern) Pos() token.Position { return dp.Pattern.Pos() }
+func (dp *DefaultPattern) End() token.Position { return dp.Default.End() }
+
+func (hp *HashPattern) Pos() token.Position { return hp.Token.Pos }
+func (hp *HashPattern) End() token.Position { return hp.Rbrace.End }
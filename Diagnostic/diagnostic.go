@@ -0,0 +1,87 @@
+package diagnostic
+
+import (
+	"fmt"
+	"strings"
+
+	token "github.com/ZooeyLang/Token"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	case Note:
+		return "note"
+	}
+	return "unknown"
+}
+
+// Span é o trecho do código fonte coberto por um diagnóstico, de Start até End (exclusivo)
+type Span struct {
+	Start token.Position
+	End   token.Position
+}
+
+// Um problema encontrado no código, com o trecho em que ele aparece e dicas de como resolvê-lo
+type Diagnostic struct {
+	Severity Severity
+	Code     string // Identificador estável do tipo do problema, ex: P001
+	Message  string
+	Span     Span
+
+	Notes       []string // Contexto extra sobre o problema
+	Suggestions []string // O que o usuário pode fazer para corrigir
+}
+
+// Cria um diagnóstico de erro cobrindo o trecho entre start e end
+func Errorf(start, end token.Position, code string, format string, a ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: Error,
+		Code:     code,
+		Message:  fmt.Sprintf(format, a...),
+		Span:     Span{Start: start, End: end},
+	}
+}
+
+// Uma linha só, no formato arquivo:linha:coluna: error[P001]: mensagem
+func (d Diagnostic) String() string {
+	header := d.Severity.String()
+	if d.Code != "" {
+		header += "[" + d.Code + "]"
+	}
+	return d.Span.Start.String() + ": " + header + ": " + d.Message
+}
+
+func (d Diagnostic) Error() string {
+	return d.String()
+}
+
+// Monta a mensagem completa: o cabeçalho, o trecho do código com o span sublinhado, as notas e as sugestões
+func (d Diagnostic) Render(source string) string {
+	var out strings.Builder
+
+	out.WriteString(d.String())
+
+	if excerpt := token.ExcerptSpan(source, d.Span.Start, d.Span.End); excerpt != "" {
+		out.WriteString("\n" + excerpt)
+	}
+	for _, note := range d.Notes {
+		out.WriteString("\n  = note: " + note)
+	}
+	for _, suggestion := range d.Suggestions {
+		out.WriteString("\n  = help: " + suggestion)
+	}
+
+	return out.String()
+}
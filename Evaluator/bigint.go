@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	object "github.com/ZooeyLang/Object"
+)
+
+// Os inteiros ficam em int64 enquanto cabem. Quando uma conta estoura, ela é refeita aqui com math/big
+// e o resultado vira um *object.BigInt; quando um resultado volta a caber em int64 ele volta a ser *object.Integer
+
+// Maior deslocamento aceito em <<, para que 1 << 1000000000000 não tente alocar a memória toda
+const maxShift = 1 << 24
+
+// Maior tamanho, em bits, do resultado de um ^ entre inteiros, para que 2 ^ 100000000000 dê erro em vez de alocar
+const maxPowerBits = 1 << 24
+
+func isInteger(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.BIGINT_OBJ
+}
+
+func normalizeInteger(value *big.Int) object.Object {
+	if value.IsInt64() {
+		return &object.Integer{Value: value.Int64()}
+	}
+	return &object.BigInt{Value: value}
+}
+
+// Os *big.Int dos objetos nunca são alterados, as contas sempre criam um valor novo
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	}
+	return nil
+}
+
+func integerToFloat(obj object.Object) float64 {
+	if integer, ok := obj.(*object.Integer); ok {
+		return float64(integer.Value)
+	}
+	value, _ := new(big.Float).SetInt(toBigInt(obj)).Float64()
+	return value
+}
+
+func evalBigIntInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toBigInt(left)
+	rightVal := toBigInt(right)
+	result := new(big.Int)
+
+	switch operator {
+	// Non-bool
+	case "^":
+		// Com expoente negativo o resultado não é inteiro, ex: 2 ^ -1 = 0.5
+		if rightVal.Sign() < 0 {
+			return &object.Float{Value: math.Pow(integerToFloat(left), integerToFloat(right))}
+		}
+		// 0, 1 e -1 continuam pequenos com qualquer expoente; os outros crescem pelo menos BitLen-1 bits por multiplicação
+		if base := new(big.Int).Abs(leftVal); base.BitLen() > 1 {
+			if !rightVal.IsInt64() || rightVal.Int64() > maxPowerBits/int64(base.BitLen()-1) {
+				return newError("exponent too large: %s", rightVal)
+			}
+		}
+		result.Exp(leftVal, rightVal, nil)
+	case "+":
+		result.Add(leftVal, rightVal)
+	case "-":
+		result.Sub(leftVal, rightVal)
+	case "*":
+		result.Mul(leftVal, rightVal)
+	case "/", "~/", "%":
+		if rightVal.Sign() == 0 {
+			return newError("attempted division by zero")
+		}
+		remainder := new(big.Int)
+		result.QuoRem(leftVal, rightVal, remainder)
+
+		// Mesmas regras dos inteiros pequenos: ~/ arredonda para baixo e % segue o sinal do divisor
+		floored := remainder.Sign() != 0 && (remainder.Sign() < 0) != (rightVal.Sign() < 0)
+		if operator == "~/" && floored {
+			result.Sub(result, big.NewInt(1))
+		}
+		if operator == "%" {
+			result = remainder
+			if floored {
+				result.Add(result, rightVal)
+			}
+		}
+	// Bitwise
+	case "&":
+		result.And(leftVal, rightVal)
+	case "|":
+		result.Or(leftVal, rightVal)
+	case "~":
+		result.Xor(leftVal, rightVal)
+	case "<<", ">>":
+		if rightVal.Sign() < 0 {
+			return newError("negative shift count: %s", rightVal)
+		}
+		if operator == ">>" {
+			// Depois de BitLen posições o resultado já é 0 ou -1
+			shift := uint(leftVal.BitLen() + 1)
+			if rightVal.IsInt64() && rightVal.Int64() < int64(shift) {
+				shift = uint(rightVal.Int64())
+			}
+			result.Rsh(leftVal, shift)
+			break
+		}
+		if !rightVal.IsInt64() || rightVal.Int64() > maxShift {
+			return newError("shift count too large: %s", rightVal)
+		}
+		result.Lsh(leftVal, uint(rightVal.Int64()))
+	// Bool
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) >= 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) <= 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	return normalizeInteger(result)
+}
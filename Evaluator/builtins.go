@@ -7,26 +7,189 @@ import (
 	object "github.com/ZooeyLang/Object"
 )
 
+// Nomes das funções nativas, que o resolver trata como sempre declaradas
+func BuiltinNames() []string {
+	names := []string{}
+	for name := range builtins {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Confere a quantidade de argumentos de uma função nativa, com a mesma mensagem das funções do usuário
+func checkArity(name string, args []object.Object, min, max int) object.Object {
+	if len(args) >= min && len(args) <= max {
+		return nil
+	}
+	if min == max {
+		return newError("wrong number of arguments to %s: expected %d, got %d", name, min, len(args))
+	}
+	return newError("wrong number of arguments to %s: expected %d to %d, got %d", name, min, max, len(args))
+}
+
 var builtins = map[string]*object.Builtin{
 	"len": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1", len(args))
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArity("len", args, 1, 1); err != nil {
+				return err
 			}
 			switch arg := args[0].(type) {
 			case *object.String:
 				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Tuple:
+				return &object.Integer{Value: int64(len(arg.Elements))}
 			default:
 				return newError("argument to `len` not supported, got %s", args[0].Type())
 			}
 		},
 	},
 	"show": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArity("show", args, 1, 1); err != nil {
+				return err
+			}
 			fmt.Println(args[0].Inspect())
 			return nil
 		},
 	},
+	// Ex: decimal(0.1), decimal("12.50"), decimal(3)
+	"decimal": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArity("decimal", args, 1, 1); err != nil {
+				return err
+			}
+			switch arg := args[0].(type) {
+			case *object.Decimal:
+				return arg
+			case *object.Integer, *object.BigInt:
+				return toDecimal(arg)
+			case *object.Float:
+				if decimal, ok := floatToDecimal(arg.Value); ok {
+					return decimal
+				}
+				return newError("cannot convert %s to DECIMAL", arg.Inspect())
+			case *object.String:
+				if decimal, ok := parseDecimal(arg.Value); ok {
+					return decimal
+				}
+				return newError("cannot convert %q to DECIMAL", arg.Value)
+			default:
+				return newError("argument to `decimal` not supported, got %s", args[0].Type())
+			}
+		},
+	},
+	"float": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArity("float", args, 1, 1); err != nil {
+				return err
+			}
+			switch arg := args[0].(type) {
+			case *object.Float:
+				return arg
+			case *object.Decimal:
+				return &object.Float{Value: decimalToFloat(arg)}
+			case *object.Integer, *object.BigInt:
+				return &object.Float{Value: integerToFloat(arg)}
+			default:
+				return newError("argument to `float` not supported, got %s", args[0].Type())
+			}
+		},
+	},
+	// Ex: round(2.675d, 2) = 2.68, round(2.5d, 0, "half_up") = 3, round(1250d, -2) = 1200
+	"round": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArity("round", args, 2, 3); err != nil {
+				return err
+			}
+			places, mode, err := roundingArguments("round", args[1:], env)
+			if err != nil {
+				return err
+			}
+			switch arg := args[0].(type) {
+			case *object.Decimal:
+				return rescale(arg, places, mode)
+			case *object.Float:
+				decimal, ok := floatToDecimal(arg.Value)
+				if !ok {
+					return arg
+				}
+				return &object.Float{Value: decimalToFloat(rescale(decimal, places, mode))}
+			default:
+				return newError("argument to `round` not supported, got %s", args[0].Type())
+			}
+		},
+	},
+	// Ex: format(12.5d, 2) = "12.50", format(1d / 3, 4) = "0.3333"
+	"format": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if err := checkArity("format", args, 2, 3); err != nil {
+				return err
+			}
+			places, mode, err := roundingArguments("format", args[1:], env)
+			if err != nil {
+				return err
+			}
+			if places < 0 {
+				return newError("`format` needs a scale of 0 or more, got %d", places)
+			}
+			var decimal *object.Decimal
+			switch arg := args[0].(type) {
+			case *object.Decimal:
+				decimal = arg
+			case *object.Integer, *object.BigInt:
+				decimal = toDecimal(arg)
+			case *object.Float:
+				converted, ok := floatToDecimal(arg.Value)
+				if !ok {
+					return &object.String{Value: arg.Inspect()}
+				}
+				decimal = converted
+			default:
+				return newError("argument to `format` not supported, got %s", args[0].Type())
+			}
+			return &object.String{Value: rescale(decimal, places, mode).Inspect()}
+		},
+	},
+	// rounding() devolve o modo atual; rounding("half_up") troca o modo e devolve o anterior
+	"rounding": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			previous := &object.String{Value: currentRoundingMode(env)}
+			if len(args) == 0 {
+				return previous
+			}
+			if err := checkArity("rounding", args, 0, 1); err != nil {
+				return err
+			}
+			mode, ok := args[0].(*object.String)
+			if !ok || !roundingModes[mode.Value] {
+				return newError("unknown rounding mode %s", args[0].Inspect())
+			}
+			env.SetRoundingMode(mode.Value)
+			return previous
+		},
+	},
+}
+
+// As casas e o modo opcional de round e format, ex: round(x, 2, "half_up")
+func roundingArguments(name string, args []object.Object, env *object.Environment) (int, string, *object.Error) {
+	places, ok := args[0].(*object.Integer)
+	if !ok {
+		return 0, "", newError("second argument to `%s` must be INTEGER, got %s", name, args[0].Type())
+	}
+	if places.Value > maxDecimalPower || places.Value < -maxDecimalPower {
+		return 0, "", newError("scale too large: %d", places.Value)
+	}
+
+	mode := currentRoundingMode(env)
+	if len(args) == 2 {
+		given, ok := args[1].(*object.String)
+		if !ok || !roundingModes[given.Value] {
+			return 0, "", newError("unknown rounding mode %s", args[1].Inspect())
+		}
+		mode = given.Value
+	}
+
+	return int(places.Value), mode, nil
 }
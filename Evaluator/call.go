@@ -0,0 +1,196 @@
+package evaluator
+
+import (
+	"fmt"
+
+	ast "github.com/ZooeyLang/AST"
+	object "github.com/ZooeyLang/Object"
+)
+
+// Um argumento passado pelo nome, como y em f(x, y: 2)
+type namedArgument struct {
+	name  string
+	value object.Object
+}
+
+// Avalia os argumentos de uma chamada da esquerda para a direita, separando os posicionais dos nomeados
+func evalArguments(exps []ast.Expression, env *object.Environment) ([]object.Object, []namedArgument, object.Object) {
+	args := []object.Object{}
+	named := []namedArgument{}
+
+	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			values, err := spreadValues(spread, env)
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, values...)
+			continue
+		}
+
+		exp := e
+		argument, isNamed := e.(*ast.NamedArgument)
+		if isNamed {
+			exp = argument.Value
+		}
+
+		evaluated := Eval(exp, env)
+		if isError(evaluated) {
+			return nil, nil, evaluated
+		}
+		if evaluated == nil {
+			evaluated = NULL
+		}
+
+		if isNamed {
+			named = append(named, namedArgument{name: argument.Name.Value, value: evaluated})
+			continue
+		}
+		args = append(args, evaluated)
+	}
+
+	return args, named, nil
+}
+
+// Declara os parâmetros num novo environment, filho do environment onde a função foi criada.
+// Os argumentos posicionais preenchem os parâmetros em ordem, os nomeados os parâmetros com o mesmo nome,
+// e os que sobram vão para o ...resto. Um parâmetro sem argumento usa o seu valor padrão
+func extendedFunctionEnv(fn *object.Function, args []object.Object, named []namedArgument) (*object.Environment, object.Object) {
+	env := object.NewEnclosedEnvironment(fn.Env)
+
+	params := fn.Parameters
+	var rest *ast.RestPattern
+	if len(params) > 0 {
+		if pattern, ok := params[len(params)-1].(*ast.RestPattern); ok {
+			rest = pattern
+			params = params[:len(params)-1]
+		}
+	}
+
+	if rest == nil && len(args) > len(params) {
+		return nil, arityError(fn, params, false, len(args))
+	}
+
+	values := make([]object.Object, len(params))
+	copy(values, args)
+
+	for _, argument := range named {
+		i := parameterIndex(params, argument.name)
+		if i < 0 {
+			return nil, newError("%s has no parameter named %s", functionName(fn), argument.name)
+		}
+		if values[i] != nil {
+			return nil, newError("%s got more than one value for parameter %s", functionName(fn), argument.name)
+		}
+		values[i] = argument.value
+	}
+
+	for i, param := range params {
+		if _, ok := param.(*ast.DefaultPattern); values[i] != nil || ok {
+			continue
+		}
+		if len(named) == 0 {
+			return nil, arityError(fn, params, rest != nil, len(args))
+		}
+		return nil, newError("missing argument for parameter %s of %s", param.String(), functionName(fn))
+	}
+
+	// Os parâmetros são declarados em ordem, então o valor padrão de um enxerga os anteriores: fn(x, y = x)
+	for i, param := range params {
+		if err := destructure(param, values[i], env); err != nil {
+			return nil, err
+		}
+	}
+
+	if rest != nil && rest.Name != nil {
+		extra := []object.Object{}
+		if len(args) > len(params) {
+			extra = append(extra, args[len(params):]...)
+		}
+		env.Set(rest.Name.Value, &object.Array{Elements: extra})
+	}
+
+	return env, nil
+}
+
+// Ex: "wrong number of arguments to soma: expected 2, got 3". Os parâmetros com valor padrão
+// depois do último obrigatório podem ficar sem argumento
+func arityError(fn *object.Function, params []ast.Pattern, rest bool, got int) object.Object {
+	required := 0
+	for i, param := range params {
+		if _, ok := param.(*ast.DefaultPattern); !ok {
+			required = i + 1
+		}
+	}
+
+	var expected string
+	switch {
+	case rest:
+		expected = fmt.Sprintf("at least %d", required)
+	case required == len(params):
+		expected = fmt.Sprintf("%d", required)
+	default:
+		expected = fmt.Sprintf("%d to %d", required, len(params))
+	}
+
+	return newError("wrong number of arguments to %s: expected %s, got %d", functionName(fn), expected, got)
+}
+
+// Só os parâmetros simples têm nome: x, x: int e x = 1. Um padrão como [a, b] só recebe argumentos posicionais
+func parameterIndex(params []ast.Pattern, name string) int {
+	for i, param := range params {
+		if def, ok := param.(*ast.DefaultPattern); ok {
+			param = def.Pattern
+		}
+
+		switch param := param.(type) {
+		case *ast.BindingPattern:
+			if param.Name.Value == name {
+				return i
+			}
+		case *ast.TypePattern:
+			if param.Name != nil && param.Name.Value == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func functionName(fn *object.Function) string {
+	if fn.FnName == "" {
+		return "anonymous function"
+	}
+	return fn.FnName
+}
+
+// x |> f(a, y: b) chama f(x, a, y: b), e x |> f chama f(x). O valor da esquerda é avaliado primeiro
+func evalOwOExpression(node *ast.OwOExpression, env *object.Environment) object.Object {
+	value := Eval(node.Left, env)
+	if isError(value) {
+		return value
+	}
+	if value == nil {
+		value = NULL
+	}
+
+	call, ok := node.Right.(*ast.CallExpression)
+	if !ok {
+		function := Eval(node.Right, env)
+		if isError(function) {
+			return function
+		}
+		return applyFunction(function, []object.Object{value}, nil, env)
+	}
+
+	function := Eval(call.Function, env)
+	if isError(function) {
+		return function
+	}
+	args, named, err := evalArguments(call.Arguments, env)
+	if err != nil {
+		return err
+	}
+
+	return applyFunction(function, append([]object.Object{value}, args...), named, env)
+}
@@ -0,0 +1,265 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	object "github.com/ZooeyLang/Object"
+)
+
+// Um Decimal é exato: 0.1d + 0.2d == 0.3d. As somas, subtrações e multiplicações nunca arredondam,
+// só a divisão e os builtins round e format, sempre usando o modo de arredondamento atual
+
+// Casas usadas numa divisão que não é exata, ex: 1d / 3 = 0.33333333333333333333
+const divisionScale = 20
+
+// Maior expoente aceito no ^ de um Decimal
+const maxDecimalPower = 10000
+
+var roundingModes = map[string]bool{
+	"half_even": true, // 2.5 -> 2, 3.5 -> 4
+	"half_up":   true, // 2.5 -> 3, -2.5 -> -3
+	"half_down": true, // 2.5 -> 2, -2.5 -> -2
+	"up":        true, // para longe do zero
+	"down":      true, // em direção ao zero
+	"ceiling":   true, // em direção ao +infinito
+	"floor":     true, // em direção ao -infinito
+}
+
+const defaultRoundingMode = "half_even"
+
+// O modo usado quando nenhum é passado. O builtin rounding troca o modo no environment global do programa,
+// então no REPL ele continua valendo entre as linhas
+func currentRoundingMode(env *object.Environment) string {
+	if mode := env.RoundingMode(); mode != "" {
+		return mode
+	}
+	return defaultRoundingMode
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Escalas negativas viram zeros no valor, para que a escala de um Decimal nunca seja negativa
+func newDecimal(unscaled *big.Int, scale int) *object.Decimal {
+	if scale < 0 {
+		return &object.Decimal{Unscaled: new(big.Int).Mul(unscaled, pow10(-scale)), Scale: 0}
+	}
+	return &object.Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+func isDecimalOperand(obj object.Object) bool {
+	return obj.Type() == object.DECIMAL_OBJ || isInteger(obj)
+}
+
+func toDecimal(obj object.Object) *object.Decimal {
+	if decimal, ok := obj.(*object.Decimal); ok {
+		return decimal
+	}
+	return &object.Decimal{Unscaled: toBigInt(obj), Scale: 0}
+}
+
+// Os dois valores na mesma escala, a maior das duas
+func alignDecimals(left, right *object.Decimal) (*big.Int, *big.Int, int) {
+	switch {
+	case left.Scale < right.Scale:
+		return new(big.Int).Mul(left.Unscaled, pow10(right.Scale-left.Scale)), right.Unscaled, right.Scale
+	case left.Scale > right.Scale:
+		return left.Unscaled, new(big.Int).Mul(right.Unscaled, pow10(left.Scale-right.Scale)), left.Scale
+	}
+	return left.Unscaled, right.Unscaled, left.Scale
+}
+
+// n / d arredondado para um inteiro seguindo o modo. d nunca é zero
+func divRound(n, d *big.Int, mode string) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(n, d, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	// O sinal do resultado exato, já que o quociente truncado pode ser 0
+	sign := n.Sign() * d.Sign()
+	// Compara o resto com a metade do divisor: -1 abaixo da metade, 0 na metade, 1 acima
+	half := new(big.Int).Abs(remainder)
+	half.Lsh(half, 1)
+	half.Sub(half, new(big.Int).Abs(d))
+
+	away := false
+	switch mode {
+	case "up":
+		away = true
+	case "down":
+		away = false
+	case "ceiling":
+		away = sign > 0
+	case "floor":
+		away = sign < 0
+	case "half_up":
+		away = half.Sign() >= 0
+	case "half_down":
+		away = half.Sign() > 0
+	default:
+		away = half.Sign() > 0 || half.Sign() == 0 && quotient.Bit(0) == 1
+	}
+
+	if away {
+		quotient.Add(quotient, big.NewInt(int64(sign)))
+	}
+	return quotient
+}
+
+// O valor com exatamente scale casas. Com scale negativo arredonda para dezenas, centenas etc,
+// ex: rescale(1250d, -2) = 1300 com half_even
+func rescale(decimal *object.Decimal, scale int, mode string) *object.Decimal {
+	if scale >= decimal.Scale {
+		return &object.Decimal{Unscaled: new(big.Int).Mul(decimal.Unscaled, pow10(scale-decimal.Scale)), Scale: scale}
+	}
+	return newDecimal(divRound(decimal.Unscaled, pow10(decimal.Scale-scale), mode), scale)
+}
+
+// Tira os zeros à direita sem deixar a escala ficar abaixo de min
+func stripZeros(decimal *object.Decimal, min int) *object.Decimal {
+	unscaled, scale := decimal.Unscaled, decimal.Scale
+	ten, remainder := big.NewInt(10), new(big.Int)
+	for scale > min {
+		quotient, _ := new(big.Int).QuoRem(unscaled, ten, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		unscaled, scale = quotient, scale-1
+	}
+	return &object.Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+func divideDecimals(left, right *object.Decimal, env *object.Environment) *object.Decimal {
+	minScale := left.Scale
+	if right.Scale > minScale {
+		minScale = right.Scale
+	}
+	scale := minScale
+	if scale < divisionScale {
+		scale = divisionScale
+	}
+
+	// (l / 10^ls) / (r / 10^rs) com scale casas é (l * 10^(scale + rs - ls)) / r
+	numerator := new(big.Int).Mul(left.Unscaled, pow10(scale+right.Scale-left.Scale))
+	result := &object.Decimal{Unscaled: divRound(numerator, right.Unscaled, currentRoundingMode(env)), Scale: scale}
+
+	// 10.00d / 4 é 2.50, e não 2.50000000000000000000
+	return stripZeros(result, minScale)
+}
+
+func evalDecimalInfixExpression(operator string, left, right object.Object, env *object.Environment) object.Object {
+	leftVal := toDecimal(left)
+	rightVal := toDecimal(right)
+	l, r, scale := alignDecimals(leftVal, rightVal)
+
+	switch operator {
+	case "+":
+		return &object.Decimal{Unscaled: new(big.Int).Add(l, r), Scale: scale}
+	case "-":
+		return &object.Decimal{Unscaled: new(big.Int).Sub(l, r), Scale: scale}
+	case "*":
+		return &object.Decimal{Unscaled: new(big.Int).Mul(leftVal.Unscaled, rightVal.Unscaled), Scale: leftVal.Scale + rightVal.Scale}
+	case "/", "~/", "%":
+		if r.Sign() == 0 {
+			return newError("attempted division by zero")
+		}
+		if operator == "/" {
+			return divideDecimals(leftVal, rightVal, env)
+		}
+		// Mesmas regras dos inteiros: ~/ arredonda para baixo e % segue o sinal do divisor
+		floored := divRound(l, r, "floor")
+		if operator == "~/" {
+			return &object.Decimal{Unscaled: floored, Scale: 0}
+		}
+		remainder := new(big.Int).Sub(l, new(big.Int).Mul(r, floored))
+		return &object.Decimal{Unscaled: remainder, Scale: scale}
+	case "^":
+		exponent, ok := right.(*object.Integer)
+		if !ok {
+			return newError("exponent of a DECIMAL must be an INTEGER, got %s", right.Type())
+		}
+		if exponent.Value > maxDecimalPower || exponent.Value < -maxDecimalPower {
+			return newError("exponent too large: %d", exponent.Value)
+		}
+		n := int(exponent.Value)
+		if n < 0 {
+			n = -n
+		}
+		power := &object.Decimal{Unscaled: new(big.Int).Exp(leftVal.Unscaled, big.NewInt(int64(n)), nil), Scale: leftVal.Scale * n}
+		if exponent.Value >= 0 {
+			return power
+		}
+		if power.Unscaled.Sign() == 0 {
+			return newError("attempted division by zero")
+		}
+		return divideDecimals(&object.Decimal{Unscaled: big.NewInt(1), Scale: 0}, power, env)
+	case "<":
+		return nativeBoolToBooleanObject(l.Cmp(r) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(l.Cmp(r) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(l.Cmp(r) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(l.Cmp(r) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(l.Cmp(r) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(l.Cmp(r) != 0)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// Ex: "12.50", "-3", "1.5e3". Retorna false quando o texto não é um número
+func parseDecimal(s string) (*object.Decimal, bool) {
+	s = strings.TrimSpace(s)
+
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	exponent := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		value, err := strconv.Atoi(s[i+1:])
+		if err != nil || value > maxDecimalPower || value < -maxDecimalPower {
+			return nil, false
+		}
+		s, exponent = s[:i], value
+	}
+
+	scale := 0
+	if i := strings.Index(s, "."); i >= 0 {
+		scale = len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return nil, false
+	}
+
+	unscaled, _ := new(big.Int).SetString(s, 10)
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+	return newDecimal(unscaled, scale-exponent), true
+}
+
+// O menor Decimal que volta para o mesmo float, ex: 0.1 vira 0.1d, e não 0.1000000000000000055511151231257827d
+func floatToDecimal(value float64) (*object.Decimal, bool) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, false
+	}
+	return parseDecimal(strconv.FormatFloat(value, 'e', -1, 64))
+}
+
+func decimalToFloat(decimal *object.Decimal) float64 {
+	value, _ := new(big.Rat).SetFrac(decimal.Unscaled, pow10(decimal.Scale)).Float64()
+	return value
+}
@@ -3,6 +3,9 @@ package evaluator
 import (
 	"fmt"
 	"math"
+	"math/big"
+	"sort"
+	"strings"
 
 	ast "github.com/ZooeyLang/AST"
 	object "github.com/ZooeyLang/Object"
@@ -15,15 +18,31 @@ var (
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := evalNode(node, env)
+
+	// O nó mais interno em que o erro aconteceu marca a sua posição
+	if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() && node != nil {
+		err.Pos = node.Pos()
+	}
+
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.LiteralInteger:
+		if node.Big != nil {
+			return &object.BigInt{Value: node.Big}
+		}
 		return &object.Integer{Value: node.Value}
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
+	case *ast.NullLiteral:
+		return NULL
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
@@ -31,6 +50,13 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
+		if node.Operator == "??" {
+			return evalNullishExpression(node, env)
+		}
+
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -41,35 +67,43 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return right
 		}
 
-		return evalInfixExpression(node.Operator, left, right)
+		return evalInfixExpression(node.Operator, left, right, env)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
-	/*case *ast.OwOExpression:
-	var result []object.Object
-
-	owoExp := EvalOwOExpression(node.Expressions, &result, env)
-
-	return (*owoExp)[0]*/
+	case *ast.OwOExpression:
+		return evalOwOExpression(node, env)
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
 			return val
 		}
 		return &object.ReturnValue{Value: val}
+	case *ast.BreakStatement:
+		if node.Label != nil {
+			return &object.Break{Label: node.Label.Value}
+		}
+		return &object.Break{}
+	case *ast.ContinueStatement:
+		if node.Label != nil {
+			return &object.Continue{Label: node.Label.Value}
+		}
+		return &object.Continue{}
 	case *ast.OwOStatement:
 		val := Eval(node.Value, env)
 		if isError(val) {
 			return val
 		}
+		if node.Pattern != nil {
+			return destructure(node.Pattern, val, env)
+		}
 		env.Set(node.Name.Value, val)
 	case *ast.BindExpression:
-		val := Eval(node.Value, env)
-		err := evalBindExpressions(node.Left, val, env)
-		if err != nil {
-			return err
-		}
+		return evalBindExpressions(node, env)
+	case *ast.FieldExpression, *ast.OptionalFieldExpression:
+		result, _ := evalMemberChain(node.(ast.Expression), env)
+		return result
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.FunctionLiteral:
@@ -78,7 +112,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		parameters := node.Parameters
 
 		fnObj := &object.Function{FnName: fnName, Parameters: parameters, Env: env, Body: body}
-		env.Set(fnName, fnObj)
+		// Funções anônimas são apenas valores; só as nomeadas são declaradas no escopo atual
+		if fnName != "" {
+			env.Set(fnName, fnObj)
+		}
 
 		return fnObj
 	case *ast.CallExpression:
@@ -86,39 +123,45 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(function) {
 			return function
 		}
-		args := evalExpressions(node.Arguments, env)
-
-		if len(args) == 1 && isError(args[0]) {
-			return args[0]
+		args, named, err := evalArguments(node.Arguments, env)
+		if err != nil {
+			return err
 		}
-		return applyFunction(function, args)
+		return applyFunction(function, args, named, env)
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
+	case *ast.TemplateLiteral:
+		return evalTemplateLiteral(node, env)
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
 			return elements[0]
 		}
 		return &object.Array{Elements: elements}
-	// Infix [
-	case *ast.IndexExpression:
-		left := Eval(node.Left, env)
-		if isError(left) {
-			return left
-		}
-		index := Eval(node.Index, env)
-		if isError(index) {
-			return index
+	case *ast.TupleLiteral:
+		elements := evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
 		}
-		return evalIndexExpression(left, index)
+		return &object.Tuple{Elements: elements}
+	// Infix [
+	case *ast.IndexExpression, *ast.OptionalIndexExpression:
+		result, _ := evalMemberChain(node.(ast.Expression), env)
+		return result
 	case *ast.LiteralFloat:
 		return &object.Float{Value: node.Value}
+	case *ast.LiteralDecimal:
+		return &object.Decimal{Unscaled: node.Unscaled, Scale: node.Scale}
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
 	case *ast.WhileExpression:
 		return evalWhileExpression(node, env)
 	case *ast.ForExpression:
 		return evalForExpression(node, env)
+	case *ast.ForInExpression:
+		return evalForInExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
 	}
 	return nil
 }
@@ -130,18 +173,90 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 		result = Eval(statement, env)
 
 		switch result := result.(type) {
-		case *object.Integer:
-			fmt.Println(result.Inspect())
+		case *object.Integer, *object.BigInt:
+			if echoes(statement) {
+				fmt.Println(result.Inspect())
+			}
 		case *object.ReturnValue:
 			return result.Value
 		case *object.Error:
 			return result
+		case *object.Break, *object.Continue:
+			return loopSignalError(result)
 		}
 	}
 
 	return result
 }
 
+// Atribuições e laços têm valor, mas só servem pelo efeito, então não são mostrados quando aparecem soltos no programa
+func echoes(statement ast.Statement) bool {
+	expression, ok := statement.(*ast.ExpressionStatement)
+	if !ok {
+		return true
+	}
+
+	switch expression.Expression.(type) {
+	case *ast.BindExpression, *ast.WhileExpression, *ast.ForExpression, *ast.ForInExpression:
+		return false
+	}
+	return true
+}
+
+// Avalia uma corrente de acessos a campos e índices, como p?.endereco.rua[0].
+// short é true quando a corrente parou num ?. ou ?[ com null; daí em diante todo o resto dela
+// também dá null, então p?.a.b não tenta ler o campo b de null
+func evalMemberChain(node ast.Expression, env *object.Environment) (result object.Object, short bool) {
+	// Como no Eval, o acesso mais interno em que o erro aconteceu marca a sua posição
+	defer func() {
+		if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() {
+			err.Pos = node.Pos()
+		}
+	}()
+
+	switch node := node.(type) {
+	case *ast.FieldExpression:
+		left, short := evalMemberChain(node.Left, env)
+		if short || isError(left) {
+			return left, short
+		}
+		return evalFieldExpression(left, node.Field.Value), false
+	case *ast.OptionalFieldExpression:
+		left, short := evalMemberChain(node.Left, env)
+		if short || isError(left) {
+			return left, short
+		}
+		if isNull(left) {
+			return NULL, true
+		}
+		return evalFieldExpression(left, node.Field.Value), false
+	case *ast.IndexExpression:
+		left, short := evalMemberChain(node.Left, env)
+		if short || isError(left) {
+			return left, short
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index, false
+		}
+		return evalIndexExpression(left, index), false
+	case *ast.OptionalIndexExpression:
+		left, short := evalMemberChain(node.Left, env)
+		if short || isError(left) {
+			return left, short
+		}
+		if isNull(left) {
+			return NULL, true
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index, false
+		}
+		return evalIndexExpression(left, index), false
+	}
+	return Eval(node, env), false
+}
+
 func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	if input {
 		return TRUE
@@ -155,68 +270,19 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 		return evalBangOperatorExpression(right)
 	case "-":
 		return evalMinusOperatorExpression(right)
+	case "~":
+		switch right := right.(type) {
+		case *object.Integer:
+			return &object.Integer{Value: ^right.Value}
+		case *object.BigInt:
+			return normalizeInteger(new(big.Int).Not(right.Value))
+		}
+		return newError("unknown operator: ~%s", right.Type())
 	default:
 		return newError("unknown operator: %s%s", operator, right.Type())
 	}
 }
 
-/* OLD AND UNSTABLE FEATURE: FIX ME
-func EvalOwOExpression(expressions []ast.Expression, result *[]object.Object, env *object.Environment) *[]object.Object {
-	var myVar []ast.Expression
-	// Get the first entry value
-	x := expressions[0]
-	myVar = append(myVar, x)
-
-	// Get the first Func
-	y := expressions[1]
-
-	function := Eval(y, env)
-
-	args := evalExpressions(myVar, env)
-
-	// Produce a value between func(entry valur)
-	newValue := applyFunction(function, args)
-
-	//fmt.Println(newValue)
-
-	*result = append(*result, newValue)
-
-	//fmt.Println(expressions)
-	// Cut func and entry value from the expressions
-	expressions = expressions[2:]
-
-	if len(expressions) == 0 {
-		return result
-	}
-
-	// fmt.Println(expressions)
-	for _, v := range expressions {
-		//var tempValue []object.Object
-		switch v.(type) {
-		// If there is more functions in "expressions"
-		case *ast.Identifier:
-			//	tempValue = append(tempValue, (*result)[0])
-			// We have to Eval it
-			function := Eval(v, env)
-
-			// Produce a new value between the last value and this function
-			newValue := applyFunction(function, *result)
-			//fmt.Println("inside", newValue)
-
-			*result = nil
-			// Keep this value at [0]
-			*result = append(*result, newValue)
-
-			// Reset tempValue
-			//tempValue = nil
-
-		}
-		// fmt.Println("resultprase", (*result)[0].Inspect())
-	}
-
-	return result
-}*/
-
 func evalBangOperatorExpression(right object.Object) object.Object {
 	switch right {
 	case TRUE:
@@ -231,26 +297,42 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 func evalMinusOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Integer:
+		// -(-9223372036854775808) não cabe em int64
+		if right.Value == math.MinInt64 {
+			return normalizeInteger(new(big.Int).Neg(big.NewInt(right.Value)))
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.BigInt:
+		return normalizeInteger(new(big.Int).Neg(right.Value))
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	case *object.Decimal:
+		return &object.Decimal{Unscaled: new(big.Int).Neg(right.Unscaled), Scale: right.Scale}
+	default:
 		return newError("unknown operator: -%s", right.Type())
 	}
-
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
 }
 
-func evalInfixExpression(operator string, left, right object.Object) object.Object {
+func evalInfixExpression(operator string, left, right object.Object, env *object.Environment) object.Object {
 	switch {
-	case left.Type() == object.INTEGER_OBJ && right == nil && operator == "++" || operator == "--":
-		return evalPostfixExpression(operator, left)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case isInteger(left) && isInteger(right):
+		// Pelo menos um dos lados é um BigInt
+		return evalBigIntInfixExpression(operator, left, right)
 	case left.Type() == object.FLOAT && right.Type() == object.FLOAT:
 		return evalFloatInfixExpression(operator, left, right)
-	case left.Type() == object.INTEGER_OBJ && right.Type() == object.FLOAT:
+	case isInteger(left) && right.Type() == object.FLOAT:
 		return evalIntLeftFloatRight(operator, left, right)
-	case left.Type() == object.FLOAT && right.Type() == object.INTEGER_OBJ:
+	case left.Type() == object.FLOAT && isInteger(right):
 		return evalIntRightFloatLeft(operator, left, right)
+	case isDecimalOperand(left) && isDecimalOperand(right):
+		// Pelo menos um dos lados é um Decimal; com um Float a conta não seria exata, então é um type mismatch
+		return evalDecimalInfixExpression(operator, left, right, env)
+	case left.Type() == object.TUPLE_OBJ && right.Type() == object.TUPLE_OBJ:
+		return evalTupleInfixExpression(operator, left.(*object.Tuple), right.(*object.Tuple), env)
 	case operator == "==":
 		return nativeBoolToBooleanObject(left == right)
 	case operator == "!=":
@@ -270,21 +352,76 @@ func evalIntegerInfixExpression(operator string, left object.Object, right objec
 
 	switch operator {
 	// Non-bool
-	case "++":
-		return &object.Integer{Value: leftVal + 1}
+	// As contas que podem estourar o int64 são refeitas com math/big quando estouram
 	case "^":
-		return &object.Integer{Value: int64(math.Pow(float64(leftVal), float64(rightVal)))}
+		return evalBigIntInfixExpression(operator, left, right)
 	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
+		sum := leftVal + rightVal
+		if (leftVal^sum)&(rightVal^sum) < 0 {
+			return evalBigIntInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: sum}
 	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
+		difference := leftVal - rightVal
+		if (leftVal^rightVal)&(leftVal^difference) < 0 {
+			return evalBigIntInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: difference}
 	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
+		product := leftVal * rightVal
+		if leftVal != 0 && (product/leftVal != rightVal || leftVal == -1 && rightVal == math.MinInt64) {
+			return evalBigIntInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: product}
 	case "/":
 		if rightVal == 0 {
 			return newError("attempted division by zero")
 		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			return evalBigIntInfixExpression(operator, left, right)
+		}
 		return &object.Integer{Value: leftVal / rightVal}
+	// O ~/ arredonda para baixo e o % segue o sinal do divisor, então a == (a ~/ b) * b + a % b
+	case "~/":
+		if rightVal == 0 {
+			return newError("attempted division by zero")
+		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			return evalBigIntInfixExpression(operator, left, right)
+		}
+		quotient := leftVal / rightVal
+		if leftVal%rightVal != 0 && (leftVal < 0) != (rightVal < 0) {
+			quotient--
+		}
+		return &object.Integer{Value: quotient}
+	case "%":
+		if rightVal == 0 {
+			return newError("attempted division by zero")
+		}
+		remainder := leftVal % rightVal
+		if remainder != 0 && (remainder < 0) != (rightVal < 0) {
+			remainder += rightVal
+		}
+		return &object.Integer{Value: remainder}
+	// Bitwise
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "~":
+		return &object.Integer{Value: leftVal ^ rightVal}
+	case "<<", ">>":
+		if rightVal < 0 {
+			return newError("negative shift count: %d", rightVal)
+		}
+		if operator == "<<" {
+			shifted := leftVal << uint64(rightVal)
+			if rightVal >= 63 || shifted>>uint64(rightVal) != leftVal {
+				return evalBigIntInfixExpression(operator, left, right)
+			}
+			return &object.Integer{Value: shifted}
+		}
+		return &object.Integer{Value: leftVal >> uint64(rightVal)}
 	// Bool
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
@@ -303,24 +440,14 @@ func evalIntegerInfixExpression(operator string, left object.Object, right objec
 	}
 }
 
-func evalPostfixExpression(operator string, left object.Object) object.Object {
-	leftVal := left.(*object.Integer).Value
-
-	switch operator {
-	case "++":
-		return &object.Integer{Value: leftVal + 1}
-	case "--":
-		return &object.Integer{Value: leftVal - 1}
-	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, nil)
-	}
-}
 func evalFloatInfixExpression(operator string, left object.Object, right object.Object) object.Object {
 	leftVal := left.(*object.Float).Value
 	rightVal := right.(*object.Float).Value
 
 	switch operator {
 	// Non-bool
+	case "^":
+		return &object.Float{Value: math.Pow(leftVal, rightVal)}
 	case "+":
 		return &object.Float{Value: leftVal + rightVal}
 	case "-":
@@ -332,11 +459,29 @@ func evalFloatInfixExpression(operator string, left object.Object, right object.
 			return newError("attempted division by zero")
 		}
 		return &object.Float{Value: leftVal / rightVal}
+	case "~/":
+		if rightVal == 0 {
+			return newError("attempted division by zero")
+		}
+		return &object.Float{Value: math.Floor(leftVal / rightVal)}
+	case "%":
+		if rightVal == 0 {
+			return newError("attempted division by zero")
+		}
+		remainder := math.Mod(leftVal, rightVal)
+		if remainder != 0 && (remainder < 0) != (rightVal < 0) {
+			remainder += rightVal
+		}
+		return &object.Float{Value: remainder}
 	// Bool
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
 		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
 	case "==":
 		return nativeBoolToBooleanObject(leftVal == rightVal)
 	case "!=":
@@ -346,60 +491,23 @@ func evalFloatInfixExpression(operator string, left object.Object, right object.
 	}
 }
 
+// Um inteiro misturado com um float vira float, e a conta segue as mesmas regras de dois floats
 func evalIntLeftFloatRight(operator string, left object.Object, right object.Object) object.Object {
-	leftVal := left.(*object.Integer).Value
-	rightVal := right.(*object.Float).Value
-
-	switch operator {
-	// Non-bool
-	case "+":
-		return &object.Float{Value: float64(leftVal) + rightVal}
-	case "-":
-		return &object.Float{Value: float64(leftVal) - rightVal}
-	case "*":
-		return &object.Float{Value: float64(leftVal) * rightVal}
-	case "/":
-		return &object.Float{Value: float64(leftVal) / rightVal}
-	// Bool
-	case "<":
-		return nativeBoolToBooleanObject(float64(leftVal) < rightVal)
-	case ">":
-		return nativeBoolToBooleanObject(float64(leftVal) > rightVal)
-	case "==":
-		return nativeBoolToBooleanObject(float64(leftVal) == rightVal)
-	case "!=":
-		return nativeBoolToBooleanObject(float64(leftVal) != rightVal)
-	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
-	}
+	result := evalFloatInfixExpression(operator, &object.Float{Value: integerToFloat(left)}, right)
+	return mixedOperandsError(result, operator, left, right)
 }
 
 func evalIntRightFloatLeft(operator string, left object.Object, right object.Object) object.Object {
-	leftVal := left.(*object.Float).Value
-	rightVal := right.(*object.Integer).Value
+	result := evalFloatInfixExpression(operator, left, &object.Float{Value: integerToFloat(right)})
+	return mixedOperandsError(result, operator, left, right)
+}
 
-	switch operator {
-	// Non-bool
-	case "+":
-		return &object.Float{Value: leftVal + float64(rightVal)}
-	case "-":
-		return &object.Float{Value: leftVal - float64(rightVal)}
-	case "*":
-		return &object.Float{Value: leftVal * float64(rightVal)}
-	case "/":
-		return &object.Float{Value: leftVal / float64(rightVal)}
-	// Bool
-	case "<":
-		return nativeBoolToBooleanObject(leftVal < float64(rightVal))
-	case ">":
-		return nativeBoolToBooleanObject(leftVal > float64(rightVal))
-	case "==":
-		return nativeBoolToBooleanObject(leftVal == float64(rightVal))
-	case "!=":
-		return nativeBoolToBooleanObject(leftVal != float64(rightVal))
-	default:
+// Mantém os tipos originais na mensagem de operador desconhecido, ex: INTEGER & FLOAT
+func mixedOperandsError(result object.Object, operator string, left, right object.Object) object.Object {
+	if err, ok := result.(*object.Error); ok && strings.HasPrefix(err.Message, "unknown operator") {
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
+	return result
 }
 
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
@@ -417,23 +525,70 @@ func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Obje
 	}
 }
 
+// Avalia && e || em curto-circuito: o lado direito só é avaliado quando o esquerdo não decide o resultado
+func evalLogicalExpression(ie *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(ie.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	if ie.Operator == "&&" && !isTruthy(left) {
+		return FALSE
+	}
+	if ie.Operator == "||" && isTruthy(left) {
+		return TRUE
+	}
+
+	right := Eval(ie.Right, env)
+	if isError(right) {
+		return right
+	}
+
+	return nativeBoolToBooleanObject(isTruthy(right))
+}
+
+// Avalia a ?? b: o lado direito só é avaliado quando o esquerdo é null
+func evalNullishExpression(ie *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(ie.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	if !isNull(left) {
+		return left
+	}
+
+	return Eval(ie.Right, env)
+}
+
+// Expressões sem valor, como a chamada de show, também contam como null
+func isNull(obj object.Object) bool {
+	return obj == nil || obj == NULL
+}
+
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
 	pairs := make(map[object.HashKey]object.HashPair)
 
-	for keyNode, valueNode := range node.Pairs {
+	for i, keyNode := range node.Keys {
+		if spread, ok := keyNode.(*ast.SpreadExpression); ok {
+			if err := spreadHash(spread, pairs, env); err != nil {
+				return err
+			}
+			continue
+		}
+
 		key := Eval(keyNode, env)
 		if isError(key) {
 			return key
 		}
-		hashKey, ok := key.(object.Hashable)
+		hashed, ok := hashKey(key)
 		if !ok {
 			return newError("Unusable as hash key: %s", key.Type())
 		}
-		value := Eval(valueNode, env)
+		value := Eval(node.Values[i], env)
 		if isError(value) {
 			return value
 		}
-		hashed := hashKey.HashKey()
 		pairs[hashed] = object.HashPair{Key: key, Value: value}
 	}
 	return &object.Hash{Pairs: pairs}
@@ -452,15 +607,20 @@ func isTruthy(obj object.Object) bool {
 	}
 }
 
+// Cada bloco tem o seu próprio escopo: um owo declarado dentro de um if ou de um laço não vaza para fora
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+	return evalStatements(block.Statements, object.NewEnclosedEnvironment(env))
+}
+
+func evalStatements(statements []ast.Statement, env *object.Environment) object.Object {
 	var result object.Object
 
-	for _, statement := range block.Statements {
+	for _, statement := range statements {
 		result = Eval(statement, env)
 
 		if result != nil {
 			rt := result.Type()
-			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ || rt == object.BREAK_OBJ || rt == object.CONTINUE_OBJ {
 				return result
 			}
 		}
@@ -479,21 +639,131 @@ func isError(obj object.Object) bool {
 	return false
 }
 
-func evalBindExpressions(name string, val object.Object, env *object.Environment) object.Object {
-	variable, _ := env.Get(name)
+// Retorna o valor guardado, então a :=: b :=: 1 guarda 1 nos dois e x++ vale o novo x
+func evalBindExpressions(be *ast.BindExpression, env *object.Environment) object.Object {
+	switch target := be.Left.(type) {
+	case *ast.Identifier:
+		name := target.Value
+		current, ok := lookupVariable(target, env)
+		if !ok {
+			return newError("identifier " + name + " not found!")
+		}
 
-	if variable != nil {
-		env.Set(name, val)
+		val := evalBindValue(be, current, env)
+		if isError(val) {
+			return val
+		}
+
+		// A atribuição escreve no escopo em que a variável foi declarada
+		if target.Binding != nil {
+			env.AssignAt(target.Binding.Depth, name, val)
+		} else {
+			env.Assign(name, val)
+		}
+		return val
+	case *ast.IndexExpression:
+		container := Eval(target.Left, env)
+		if isError(container) {
+			return container
+		}
+		index := Eval(target.Index, env)
+		if isError(index) {
+			return index
+		}
+
+		return evalIndexAssignment(be, container, index, env)
+	case *ast.FieldExpression:
+		container := Eval(target.Left, env)
+		if isError(container) {
+			return container
+		}
+		if container.Type() != object.HASH_OBJ {
+			return newError("field assignment not supported: %s", container.Type())
+		}
+
+		return evalIndexAssignment(be, container, &object.String{Value: target.Field.Value}, env)
+	default:
+		return newError("cannot assign to %s", be.Left.String())
+	}
+}
+
+// Calcula o novo valor de uma atribuição; current só é usado pelos operadores compostos (+=, ++, ...)
+func evalBindValue(be *ast.BindExpression, current object.Object, env *object.Environment) object.Object {
+	if be.Operator == ":=:" {
+		// Uma expressão sem valor, como show(x), guarda null
+		if value := Eval(be.Value, env); value != nil {
+			return value
+		}
+		return NULL
+	}
+
+	var right object.Object
+	if be.Value == nil {
+		// i++ e i-- equivalem a i += 1 e i -= 1
+		right = &object.Integer{Value: 1}
 	} else {
-		return newError("identifier " + name + " not found!")
+		right = Eval(be.Value, env)
+		if isError(right) {
+			return right
+		}
 	}
-	return nil
 
+	return evalInfixExpression(be.Operator[:1], current, right, env)
+}
+
+// Altera o elemento do array ou hash no próprio objeto, sem reconstruir a coleção
+func evalIndexAssignment(be *ast.BindExpression, container, index object.Object, env *object.Environment) object.Object {
+	switch container := container.(type) {
+	case *object.Array:
+		i, ok := index.(*object.Integer)
+		if !ok {
+			return newError("array index must be INTEGER, got %s", index.Type())
+		}
+		if i.Value < 0 || i.Value >= int64(len(container.Elements)) {
+			return newError("index out of range: %d", i.Value)
+		}
+
+		val := evalBindValue(be, container.Elements[i.Value], env)
+		if isError(val) {
+			return val
+		}
+		container.Elements[i.Value] = val
+		return val
+	case *object.Hash:
+		hashed, ok := hashKey(index)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+
+		var current object.Object = NULL
+		if pair, ok := container.Pairs[hashed]; ok {
+			current = pair.Value
+		}
+
+		val := evalBindValue(be, current, env)
+		if isError(val) {
+			return val
+		}
+		container.Pairs[hashed] = object.HashPair{Key: index, Value: val}
+		return val
+	case *object.Tuple:
+		return newError("cannot assign to an element of a tuple, tuples are immutable")
+	default:
+		return newError("index assignment not supported: %s", container.Type())
+	}
+}
+
+// Usa o escopo calculado pelo resolver quando ele existe, senão procura pela cadeia de environments
+func lookupVariable(node *ast.Identifier, env *object.Environment) (object.Object, bool) {
+	if node.Binding != nil {
+		return env.GetAt(node.Binding.Depth, node.Value)
+	}
+	return env.Get(node.Value)
 }
 
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
 
-	if val, ok := env.Get(node.Value); ok {
+	if val, ok := lookupVariable(node, env); ok {
 		return val
 	}
 
@@ -507,6 +777,15 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	var result []object.Object
 
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			values, err := spreadValues(spread, env)
+			if err != nil {
+				return []object.Object{err}
+			}
+			result = append(result, values...)
+			continue
+		}
+
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
@@ -518,31 +797,31 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 }
 
 // Cria um novo environment para aquela função, uma especie de escopo aonde as variaveis se mantém
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+func applyFunction(fn object.Object, args []object.Object, named []namedArgument, env *object.Environment) object.Object {
 
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendedFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
+		extendedEnv, err := extendedFunctionEnv(fn, args, named)
+		if err != nil {
+			return err
+		}
+		// O corpo da função compartilha o escopo dos parâmetros
+		evaluated := evalStatements(fn.Body.Statements, extendedEnv)
+		if isLoopSignal(evaluated) {
+			return loopSignalError(evaluated)
+		}
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
-		return fn.Fn(args...)
+		if len(named) > 0 {
+			return newError("builtin functions do not accept named arguments, got %s", named[0].name)
+		}
+		return fn.Fn(env, args...)
 	default:
 		return newError("Not a function: %s", fn.Type())
 	}
 
 }
 
-func extendedFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
-	env := object.NewEnclosedEnvironment(fn.Env)
-
-	for paramID, param := range fn.Parameters {
-		// Para cada parametro da função, guardamos dentro desse novo escopo o valor correspondente a ele
-		env.Set(param.Value, args[paramID])
-	}
-	return env
-}
-
 // Se encontrar um retorno, não retorne "return" e sim o valor dele, para não parar outras execuções
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
@@ -563,11 +842,32 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
 	return &object.String{Value: leftVal + rightVal}
 }
 
+// Junta o texto e o valor de cada expressão, no mesmo formato que o show usa
+func evalTemplateLiteral(template *ast.TemplateLiteral, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	for _, part := range template.Parts {
+		value := Eval(part, env)
+		if isError(value) {
+			return value
+		}
+		// Funções como o show não retornam nada
+		if value == nil {
+			value = NULL
+		}
+		out.WriteString(value.Inspect())
+	}
+
+	return &object.String{Value: out.String()}
+}
+
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	// array[x]
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.TUPLE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalArrayIndexExpression(&object.Array{Elements: left.(*object.Tuple).Elements}, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
 	default:
@@ -588,19 +888,98 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 	return arrayObject.Elements[idx]
 }
 
+// Os pares de um hash ordenados pela chave, para que a iteração seja sempre na mesma ordem
+func sortedHashPairs(hash *object.Hash) []object.HashPair {
+	pairs := make([]object.HashPair, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		pairs = append(pairs, pair)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		left, right := pairs[i].Key, pairs[j].Key
+		// Inteiros, pequenos ou grandes, ficam em ordem numérica
+		if isInteger(left) && isInteger(right) {
+			return toBigInt(left).Cmp(toBigInt(right)) < 0
+		}
+		if left.Type() != right.Type() {
+			return left.Type() < right.Type()
+		}
+		return left.Inspect() < right.Inspect()
+	})
+
+	return pairs
+}
+
+func evalFieldExpression(left object.Object, field string) object.Object {
+	if left.Type() != object.HASH_OBJ {
+		return newError("field access not supported: %s", left.Type())
+	}
+
+	return evalHashIndexExpression(left, &object.String{Value: field})
+}
+
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
-	key, ok := index.(object.Hashable)
+	key, ok := hashKey(index)
 	if !ok {
 		return newError("unusable as hash key: %s", index.Type())
 	}
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	pair, ok := hashObject.Pairs[key]
 	if !ok {
 		return NULL
 	}
 	return pair.Value
 }
 
+// O que um laço deve fazer depois de avaliar o seu corpo
+const (
+	loopNext = iota // segue para a próxima volta
+	loopStop        // encerra o laço normalmente
+	loopExit        // encerra o laço e propaga o resultado (return, erro ou break/continue de outro laço)
+)
+
+func loopControl(result object.Object, label string) int {
+	switch result := result.(type) {
+	case *object.ReturnValue, *object.Error:
+		return loopExit
+	case *object.Break:
+		if result.Label == "" || result.Label == label {
+			return loopStop
+		}
+		return loopExit
+	case *object.Continue:
+		if result.Label == "" || result.Label == label {
+			return loopNext
+		}
+		return loopExit
+	}
+	return loopNext
+}
+
+func isLoopSignal(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Break, *object.Continue:
+		return true
+	}
+	return false
+}
+
+// break e continue que escaparam de todos os laços (ou de uma função) viram erro
+func loopSignalError(signal object.Object) object.Object {
+	label := ""
+	switch signal := signal.(type) {
+	case *object.Break:
+		label = signal.Label
+	case *object.Continue:
+		label = signal.Label
+	}
+
+	if label != "" {
+		return newError("%s: no enclosing loop labeled %s", signal.Inspect(), label)
+	}
+	return newError("%s outside of a loop", signal.Inspect())
+}
+
 func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) object.Object {
 	var result object.Object
 
@@ -610,11 +989,22 @@ func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) objec
 			return condition
 		}
 
-		if isTruthy(condition) {
-			result = Eval(we.Consequence, env)
-		} else {
+		if !isTruthy(condition) {
+			break
+		}
+
+		body := Eval(we.Consequence, env)
+
+		control := loopControl(body, we.Label)
+		if control == loopExit {
+			return body
+		}
+		if control == loopStop {
 			break
 		}
+		if !isLoopSignal(body) {
+			result = body
+		}
 	}
 
 	return result
@@ -624,22 +1014,120 @@ func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) objec
 func evalForExpression(fe *ast.ForExpression, env *object.Environment) object.Object {
 	var result object.Object
 
-	Eval(fe.Identifier, env)
+	// As variáveis declaradas no init pertencem ao laço e não vazam para o escopo externo
+	loopEnv := object.NewEnclosedEnvironment(env)
+
+	for _, statement := range fe.Init {
+		initial := Eval(statement, loopEnv)
+		if isError(initial) {
+			return initial
+		}
+	}
 
 	for {
-		condition := Eval(fe.Condition, env)
-		if isError(condition) {
-			return condition
+		if fe.Condition != nil {
+			condition := Eval(fe.Condition, loopEnv)
+			if isError(condition) {
+				return condition
+			}
+
+			if !isTruthy(condition) {
+				break
+			}
 		}
 
-		if isTruthy(condition) {
-			Eval(fe.Aggregator, env)
-			result = Eval(fe.Consequence, env)
-		} else {
+		body := Eval(fe.Consequence, loopEnv)
+
+		control := loopControl(body, fe.Label)
+		if control == loopExit {
+			return body
+		}
+		if control == loopStop {
 			break
 		}
+		if !isLoopSignal(body) {
+			result = body
+		}
+
+		// Cada volta ganha uma cópia das variáveis do laço, assim as closures criadas no corpo
+		// guardam o valor daquela volta e não o valor final
+		loopEnv = loopEnv.Copy()
+
+		// O passo roda depois do corpo, inclusive quando ele termina com continue
+		for _, statement := range fe.Post {
+			post := Eval(statement, loopEnv)
+			if isError(post) {
+				return post
+			}
+		}
 	}
 
 	return result
 
 }
+
+func evalForInExpression(fe *ast.ForInExpression, env *object.Environment) object.Object {
+	iterable := Eval(fe.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	var keys, values []object.Object
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for index, element := range iterable.Elements {
+			keys = append(keys, &object.Integer{Value: int64(index)})
+			values = append(values, element)
+		}
+	case *object.Tuple:
+		for index, element := range iterable.Elements {
+			keys = append(keys, &object.Integer{Value: int64(index)})
+			values = append(values, element)
+		}
+	case *object.Hash:
+		for _, pair := range sortedHashPairs(iterable) {
+			keys = append(keys, pair.Key)
+			values = append(values, pair.Value)
+		}
+		// Com uma só variável, iterar um hash percorre as suas chaves
+		if fe.Key == nil {
+			values = keys
+		}
+	case *object.String:
+		index := 0
+		for _, char := range iterable.Value {
+			keys = append(keys, &object.Integer{Value: int64(index)})
+			values = append(values, &object.String{Value: string(char)})
+			index++
+		}
+	default:
+		return newError("cannot iterate over %s", iterable.Type())
+	}
+
+	var result object.Object
+
+	for i := range values {
+		// Cada volta tem o seu próprio escopo com as variáveis do laço
+		loopEnv := object.NewEnclosedEnvironment(env)
+		if fe.Key != nil {
+			loopEnv.Set(fe.Key.Value, keys[i])
+		}
+		loopEnv.Set(fe.Value.Value, values[i])
+
+		body := Eval(fe.Consequence, loopEnv)
+
+		control := loopControl(body, fe.Label)
+		if control == loopExit {
+			return body
+		}
+		if control == loopStop {
+			break
+		}
+		if !isLoopSignal(body) {
+			result = body
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,1024 @@
+package evaluator
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	lexer "github.com/ZooeyLang/Lexer"
+	object "github.com/ZooeyLang/Object"
+	parser "github.com/ZooeyLang/Parser"
+	resolver "github.com/ZooeyLang/Resolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEval(t *testing.T, input string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	assert.Empty(t, p.Errors(), "The program must parse without errors!")
+
+	return Eval(program, object.NewEnvironment())
+}
+
+func TestEval_LogicalOperators(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "and with both sides truthy", input: "true && 1", want: "true"},
+		{name: "and with falsy left side", input: "false && true", want: "false"},
+		{name: "or with truthy left side", input: "1 > 2 || 2 > 1", want: "true"},
+		{name: "or with both sides falsy", input: "false || false", want: "false"},
+		{name: "and binds tighter than or", input: "true || false && false", want: "true"},
+		{name: "comparison binds tighter than and", input: "1 < 2 && 3 == 3", want: "true"},
+		{
+			name:  "and should not evaluate the right side when the left side is falsy",
+			input: "false && naoExiste",
+			want:  "false",
+		},
+		{
+			name:  "or should not evaluate the right side when the left side is truthy",
+			input: "true || naoExiste",
+			want:  "true",
+		},
+		{
+			name:  "right side is evaluated when the left side does not decide",
+			input: "true && naoExiste",
+			want:  "ERROR: identifier not found: naoExiste",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_FunctionLiterals(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "named functions are declared in the current scope",
+			input: "fn dobro(x) { x * 2 }; dobro(21)",
+			want:  "42",
+		},
+		{
+			name:  "anonymous functions can be bound and called",
+			input: "owo dobro :=: fn(x) { x * 2 }; dobro(4)",
+			want:  "8",
+		},
+		{
+			name:  "anonymous functions can be called inline",
+			input: "fn(x, y) { x + y }(1, 2)",
+			want:  "3",
+		},
+		{
+			name:  "anonymous functions can be passed as callbacks",
+			input: "fn aplica(f, x) { f(x) }; aplica(fn(x) { x + 1 }, 9)",
+			want:  "10",
+		},
+		{
+			name:  "closures capture their defining environment",
+			input: "fn somador(x) { fn(y) { x + y } }; owo somaDois :=: somador(2); somaDois(3)",
+			want:  "5",
+		},
+		{
+			name:  "anonymous functions can recurse through their binding",
+			input: "owo fat :=: fn(n) { if n < 2 { return 1 } return n * fat(n - 1) }; fat(5)",
+			want:  "120",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_LoopControlFlow(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "break stops a while loop",
+			input: "owo i :=: 0; while(true) { if i == 3 { break } i :=: i + 1 }; i",
+			want:  "3",
+		},
+		{
+			name: "continue skips the rest of the body",
+			input: `owo i :=: 0; owo soma :=: 0;
+			while(i < 5) { i :=: i + 1; if i == 2 { continue } soma :=: soma + i }; soma`,
+			want: "13",
+		},
+		{
+			name:  "break stops a for loop",
+			input: "owo ultimo :=: 0; for(owo i :=: 0; i < 100; i++) { if i == 4 { break } ultimo :=: i }; ultimo",
+			want:  "3",
+		},
+		{
+			name: "labeled break stops the outer loop",
+			input: `owo voltas :=: 0; owo i :=: 0;
+			externo: while(i < 10) {
+				i :=: i + 1
+				owo j :=: 0
+				while(j < 10) {
+					j :=: j + 1
+					voltas :=: voltas + 1
+					if j == 2 { break externo }
+				}
+			}; voltas`,
+			want: "2",
+		},
+		{
+			name: "labeled continue moves the outer loop forward",
+			input: `owo voltas :=: 0; owo i :=: 0;
+			externo: while(i < 3) {
+				i :=: i + 1
+				owo j :=: 0
+				while(j < 10) {
+					j :=: j + 1
+					voltas :=: voltas + 1
+					continue externo
+				}
+			}; voltas`,
+			want: "3",
+		},
+		{
+			name:  "return inside a loop leaves the function",
+			input: "fn primeiro() { owo i :=: 0; while(true) { i :=: i + 1; if i == 7 { return i } } }; primeiro()",
+			want:  "7",
+		},
+		{
+			name:  "errors inside a loop stop it",
+			input: "owo i :=: 0; while(i < 3) { i :=: i + 1; naoExiste }",
+			want:  "ERROR: identifier not found: naoExiste",
+		},
+		{
+			name:  "break outside of a loop is an error",
+			input: "break",
+			want:  "ERROR: break outside of a loop",
+		},
+		{
+			name:  "continue cannot escape a function",
+			input: "fn f() { continue }; while(true) { f() }",
+			want:  "ERROR: continue outside of a loop",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_ForInExpression(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "iterates over array elements",
+			input: "owo soma :=: 0; for nota in [7, 8, 5] { soma :=: soma + nota }; soma",
+			want:  "20",
+		},
+		{
+			name:  "iterates over array indexes and elements",
+			input: `owo s :=: ""; for i, letra in ["a", "b"] { s :=: s + letra; if i == 0 { s :=: s + "-" } }; s`,
+			want:  "a-b",
+		},
+		{
+			name:  "iterates over hash keys",
+			input: `owo s :=: ""; for chave in {"b": 2, "a": 1} { s :=: s + chave }; s`,
+			want:  "ab",
+		},
+		{
+			name:  "iterates over hash keys and values",
+			input: `owo soma :=: 0; for chave, valor in {"a": 1, "b": 2, "c": 3} { soma :=: soma + valor }; soma`,
+			want:  "6",
+		},
+		{
+			name:  "iterates over strings rune by rune",
+			input: `owo s :=: ""; for c in "ação" { s :=: c + s }; s`,
+			want:  "oãça",
+		},
+		{
+			name:  "string indexes count runes",
+			input: `owo ultimo :=: 0; for i, c in "ação" { ultimo :=: i }; ultimo`,
+			want:  "3",
+		},
+		{
+			name:  "loop variables are scoped to the body",
+			input: "for x in [1, 2] { x }; x",
+			want:  "ERROR: identifier not found: x",
+		},
+		{
+			name:  "loop variables do not clobber outer variables",
+			input: "owo x :=: 10; for x in [1, 2] { x }; x",
+			want:  "10",
+		},
+		{
+			name:  "break and continue work inside for-in",
+			input: "owo soma :=: 0; for n in [1, 2, 3, 4, 5] { if n == 2 { continue } if n == 4 { break } soma :=: soma + n }; soma",
+			want:  "4",
+		},
+		{
+			name:  "iterating a non-iterable value is an error",
+			input: "for x in 5 { x }",
+			want:  "ERROR: cannot iterate over INTEGER",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_ForExpression(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "body sees the initial value first",
+			input: `owo s :=: ""; for(owo i :=: 0; i < 3; i++) { s :=: s + "i" ; if i == 0 { s :=: "zero" } }; s`,
+			want:  "zeroii",
+		},
+		{
+			name:  "runs once per value up to the condition",
+			input: "owo voltas :=: 0; for(owo i :=: 0; i <= 10; i++) { voltas :=: voltas + 1 }; voltas",
+			want:  "11",
+		},
+		{
+			name:  "post step runs after continue",
+			input: "owo soma :=: 0; for(owo i :=: 0; i < 5; i++) { if i == 2 { continue } soma :=: soma + i }; soma",
+			want:  "8",
+		},
+		{
+			name:  "multi-variable init and post",
+			input: "owo passos :=: 0; for(owo i :=: 0, j :=: 10; i < j; i++, j--) { passos :=: passos + 1 }; passos",
+			want:  "5",
+		},
+		{
+			name:  "every clause is optional",
+			input: "owo i :=: 0; for(;;) { i :=: i + 1; if i == 4 { break } }; i",
+			want:  "4",
+		},
+		{
+			name:  "init and post can be any expression",
+			input: "owo i :=: 0; for(i :=: 3; i > 0; i :=: i - 1) { }; i",
+			want:  "0",
+		},
+		{
+			name:  "loop variable does not leak into the enclosing scope",
+			input: "for(owo i :=: 0; i < 2; i++) { }; i",
+			want:  "ERROR: identifier not found: i",
+		},
+		{
+			name:  "errors in the post step stop the loop",
+			input: "for(owo i :=: 0; i < 2; naoExiste) { }",
+			want:  "ERROR: identifier not found: naoExiste",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_AssignmentTargets(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "identifier", input: "owo x :=: 1; x :=: 2; x", want: "2"},
+		{name: "compound operators", input: "owo x :=: 10; x += 5; x -= 3; x *= 2; x /= 4; x", want: "6"},
+		{name: "postfix increment and decrement", input: "owo x :=: 1; x++; x++; x--; x", want: "2"},
+		{name: "array element", input: "owo notas :=: [1, 2, 3]; notas[1] :=: 20; notas", want: "[1, 20, 3]"},
+		{name: "compound array element", input: "owo notas :=: [1, 2, 3]; notas[2] *= 10; notas[0]++; notas", want: "[2, 2, 30]"},
+		{name: "hash element", input: `owo h :=: {"a": 1}; h["a"] += 1; h["a"]`, want: "2"},
+		{name: "new hash key", input: `owo h :=: {}; h["novo"] :=: 7; h["novo"]`, want: "7"},
+		{name: "dotted field", input: `owo p :=: {"idade": 30}; p.idade :=: 31; p.idade`, want: "31"},
+		{name: "compound dotted field", input: `owo p :=: {"idade": 30}; p.idade++; p["idade"]`, want: "31"},
+		{name: "nested targets", input: `owo m :=: {"l": [1, 2]}; m.l[1] :=: 5; m["l"]`, want: "[1, 5]"},
+		{name: "chained assignment", input: "owo x :=: 0; owo y :=: 0; x :=: y :=: 1; x + y", want: "2"},
+		{name: "chained element assignment", input: `owo a :=: [0]; owo h :=: {}; owo x :=: 0; x :=: a[0] :=: h["k"] :=: 3; [x, a, h["k"]]`, want: "[3, [3], 3]"},
+		{name: "assignment evaluates to the stored value", input: "owo x :=: 1; [x :=: 5, x += 2, x++]", want: "[5, 7, 8]"},
+		{name: "assigning a value-less expression stores null", input: "fn nada() { }; owo x :=: 1; x :=: nada(); x", want: "null"},
+		{
+			name:  "collections are updated in place",
+			input: "owo a :=: [1, 2]; owo b :=: a; b[0] :=: 9; a",
+			want:  "[9, 2]",
+		},
+		{name: "array index out of range", input: "owo a :=: [1]; a[3] :=: 2", want: "ERROR: index out of range: 3"},
+		{name: "unknown identifier", input: "y :=: 2", want: "ERROR: identifier y not found!"},
+		{name: "field of a non-hash", input: "owo a :=: 1; a.x :=: 2", want: "ERROR: field assignment not supported: INTEGER"},
+		{name: "field access", input: `owo p :=: {"nome": "Zooey"}; p.nome`, want: "Zooey"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func testEvalResolved(t *testing.T, input string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	assert.Empty(t, p.Errors(), "The program must parse without errors!")
+
+	r := resolver.New(BuiltinNames()...)
+	r.Resolve(program)
+
+	assert.Empty(t, r.Errors(), "The program must resolve without errors!")
+
+	return Eval(program, object.NewEnvironment())
+}
+
+// Roda o programa e devolve o que ele escreveu na saída padrão
+func captureOutput(t *testing.T, input string) string {
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	testEval(t, input)
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(output)
+}
+
+func TestEval_ProgramEchoesOnlyValues(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "assignments are not echoed", input: "owo x :=: 1; x++; x += 3; x :=: 7", want: ""},
+		{name: "loops are not echoed", input: "owo n :=: 0; while(n < 3) { n += 1 }; for(owo i :=: 0; i < 2; i++) { n += i }; for v in [1] { n += v }", want: ""},
+		{name: "integer expressions are echoed", input: "owo x :=: 1; x++; x + 1", want: "3\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, captureOutput(t, tc.input))
+		})
+	}
+}
+
+func TestEval_ResolvedAssignment(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "assignment inside a function updates the outer variable",
+			input: `owo teste :=: "antes"; fn muda() { teste :=: "depois" }; muda(); teste`,
+			want:  "depois",
+		},
+		{
+			name:  "closures keep their own counter",
+			input: "fn contador() { owo n :=: 0; fn() { n++; n } }; owo c :=: contador(); c(); c(); c()",
+			want:  "3",
+		},
+		{
+			name:  "a global read before a local shadow is declared uses the global",
+			input: "owo x :=: 1; fn f() { owo antes :=: x; owo x :=: 2; antes * 10 + x }; f()",
+			want:  "12",
+		},
+		{
+			name:  "loops update the variables of the enclosing function",
+			input: "fn soma(ns) { owo total :=: 0; for n in ns { total += n }; total }; soma([1, 2, 3])",
+			want:  "6",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_BlockScoping(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "owo inside a block does not clobber an outer variable",
+			input: "owo x :=: 1; if true { owo x :=: 2 }; x",
+			want:  "1",
+		},
+		{
+			name:  "assignment inside a block reaches the outer variable",
+			input: "owo x :=: 1; if true { x :=: 2 }; x",
+			want:  "2",
+		},
+		{
+			name:  "each while iteration gets a fresh scope",
+			input: "owo i :=: 0; owo soma :=: 0; while(i < 3) { owo dobro :=: i * 2; soma += dobro; i++ }; soma",
+			want:  "6",
+		},
+		{
+			name: "closures created in a for loop capture that iteration",
+			input: `owo fs :=: [0, 0, 0];
+			for(owo i :=: 0; i < 3; i++) { fs[i] :=: fn() { i } };
+			[fs[0](), fs[1](), fs[2]()]`,
+			want: "[0, 1, 2]",
+		},
+		{
+			name: "closures created in a for-in loop capture that iteration",
+			input: `owo fs :=: [0, 0];
+			for i, x in [10, 20] { owo dobro :=: x * 2; fs[i] :=: fn() { dobro } };
+			[fs[0](), fs[1]()]`,
+			want: "[20, 40]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_ErrorPositions(t *testing.T) {
+	source := "owo notas :=: [7, 8]\nfn media(ns) {\n\tns[0] + naoExiste\n}\nmedia(notas)"
+
+	evaluated := testEval(t, source)
+
+	err, ok := evaluated.(*object.Error)
+	assert.True(t, ok, "The program must fail!")
+	assert.Equal(t, "3:10", err.Pos.String())
+	assert.Equal(t, "3:10: ERROR: identifier not found: naoExiste\n\tns[0] + naoExiste\n\t        ^", err.Report(source))
+}
+
+func TestEval_TemplateLiteral(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "mixes numbers, booleans and strings",
+			input: `owo media :=: 7; fn passou(n) { n > 6 }; "média: ${media} (${passou(media)})"`,
+			want:  "média: 7 (true)",
+		},
+		{name: "expressions can hold strings and braces", input: `"${ {"a": "b"}["a"] + "c" }!"`, want: "bc!"},
+		{name: "nested templates", input: `owo n :=: 2; "${"n = ${n}"}"`, want: "n = 2"},
+		{name: "adjacent interpolations", input: `"${1}${false}${[1, 2]}"`, want: "1false[1, 2]"},
+		{name: "escaped dollar stays literal", input: `"\${1}"`, want: "${1}"},
+		{name: "unicode identifiers", input: `owo média :=: 7; owo ação :=: "passou"; "${ação} com ${média}"`, want: "passou com 7"},
+		{name: "errors inside an interpolation propagate", input: `"x: ${naoExiste}"`, want: "ERROR: identifier not found: naoExiste"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_ArithmeticAndBitwiseOperators(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "exact integer power", input: "3 ^ 39", want: "4052555153018976267"},
+		{name: "negative exponent gives a float", input: "2 ^ -2", want: "0.250000"},
+		{name: "float power", input: "2.0 ^ 0.5 > 1.41", want: "true"},
+		{name: "modulo", input: "17 % 5", want: "2"},
+		{name: "modulo follows the divisor sign", input: "-7 % 3", want: "2"},
+		{name: "floor division", input: "7 ~/ 2", want: "3"},
+		{name: "floor division rounds down", input: "-7 ~/ 2", want: "-4"},
+		{name: "floor division and modulo agree", input: "owo a :=: -7; owo b :=: 3; (a ~/ b) * b + a % b == a", want: "true"},
+		{name: "float floor division", input: "7.5 ~/ 2.0", want: "3.000000"},
+		{name: "float modulo", input: "-7.5 % 2.0", want: "0.500000"},
+		{name: "bitwise and, or, xor", input: "[12 & 10, 12 | 10, 12 ~ 10]", want: "[8, 14, 6]"},
+		{name: "bitwise not", input: "~5", want: "-6"},
+		{name: "shifts", input: "[1 << 10, -16 >> 2]", want: "[1024, -4]"},
+		{name: "negative shift", input: "1 << -1", want: "ERROR: negative shift count: -1"},
+		{name: "modulo by zero", input: "1 % 0", want: "ERROR: attempted division by zero"},
+		{name: "floor division by zero", input: "1 ~/ 0", want: "ERROR: attempted division by zero"},
+		{name: "float comparisons", input: "[1.5 <= 1.5, 2.5 >= 3.0]", want: "[true, false]"},
+		{name: "negative float", input: "-1.5 + 1", want: "-0.500000"},
+		{name: "int and float mix like two floats", input: "[7 % 2.5, 2.0 ^ 3, 1 <= 1.5, 9.0 >= 9]", want: "[2.000000, 8.000000, true, true]"},
+		{name: "mixed division by zero", input: "1 / 0.0", want: "ERROR: attempted division by zero"},
+		{name: "bitwise needs integers", input: "1 & 2.0", want: "ERROR: unknown operator: INTEGER & FLOAT"},
+		{name: "bitwise not needs an integer", input: "~1.5", want: "ERROR: unknown operator: ~FLOAT"},
+		{name: "shift binds tighter than bitwise and", input: "1 << 2 & 4", want: "4"},
+		{name: "bitwise binds tighter than comparison", input: "6 & 3 == 2", want: "true"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_BigIntegers(t *testing.T) {
+	type test struct {
+		name     string
+		input    string
+		want     string
+		wantType object.ObjectType
+	}
+
+	tests := []test{
+		{name: "sum overflow promotes", input: "9223372036854775807 + 1", want: "9223372036854775808", wantType: object.BIGINT_OBJ},
+		{name: "subtraction overflow promotes", input: "-9223372036854775807 - 2", want: "-9223372036854775809", wantType: object.BIGINT_OBJ},
+		{name: "multiplication overflow promotes", input: "4294967296 * 4294967296", want: "18446744073709551616", wantType: object.BIGINT_OBJ},
+		{name: "power is exact", input: "2 ^ 100", want: "1267650600228229401496703205376", wantType: object.BIGINT_OBJ},
+		{name: "shift overflow promotes", input: "1 << 64", want: "18446744073709551616", wantType: object.BIGINT_OBJ},
+		{name: "negating the smallest integer promotes", input: "-(-9223372036854775807 - 1)", want: "9223372036854775808", wantType: object.BIGINT_OBJ},
+		{name: "big literal", input: "123456789012345678901234567890", want: "123456789012345678901234567890", wantType: object.BIGINT_OBJ},
+		{name: "results that fit demote", input: "(9223372036854775807 + 10) - 20", want: "9223372036854775797", wantType: object.INTEGER_OBJ},
+		{name: "big division demotes", input: "2 ^ 100 / 2 ^ 98", want: "4", wantType: object.INTEGER_OBJ},
+		{name: "big floor division and modulo", input: "[-(2 ^ 70) ~/ 3, -(2 ^ 70) % 3]", want: "[-393530540239137101142, 2]", wantType: object.ARRAY_OBJ},
+		{name: "big bitwise", input: "(2 ^ 70 | 1) & 3", want: "1", wantType: object.INTEGER_OBJ},
+		{name: "big bitwise not", input: "~(2 ^ 64)", want: "-18446744073709551617", wantType: object.BIGINT_OBJ},
+		{name: "big right shift", input: "[2 ^ 70 >> 69, -(2 ^ 70) >> 1000]", want: "[2, -1]", wantType: object.ARRAY_OBJ},
+		{name: "big comparisons", input: "[2 ^ 64 > 1, 2 ^ 64 == 2 ^ 64, 1 <= -(2 ^ 64)]", want: "[true, true, false]", wantType: object.ARRAY_OBJ},
+		{name: "big mixed with float", input: "2 ^ 64 * 0.5 == 9223372036854775808.0", want: "true", wantType: object.BOOLEAN_OBJ},
+		{name: "big negative power", input: "(2 ^ 64) ^ -1 < 0.0000001", want: "true", wantType: object.BOOLEAN_OBJ},
+		{name: "big division by zero", input: "2 ^ 64 % 0", want: "ERROR: attempted division by zero", wantType: object.ERROR_OBJ},
+		{name: "huge shifts are rejected", input: "1 << 2 ^ 40", want: "ERROR: shift count too large: 1099511627776", wantType: object.ERROR_OBJ},
+		{name: "huge powers are rejected", input: "2 ^ 100000000000", want: "ERROR: exponent too large: 100000000000", wantType: object.ERROR_OBJ},
+		{name: "huge powers of big bases are rejected", input: "(2 ^ 64) ^ 300000", want: "ERROR: exponent too large: 300000", wantType: object.ERROR_OBJ},
+		{name: "powers of 0, 1 and -1 accept any exponent", input: "[0 ^ 100000000000, 1 ^ 100000000000, (-1) ^ 100000000001]", want: "[0, 1, -1]", wantType: object.ARRAY_OBJ},
+		{name: "large powers within the limit", input: "len(\"${2 ^ 100000}\")", want: "30103", wantType: object.INTEGER_OBJ},
+		{name: "factorial", input: "fn fat(n) { if n < 2 { return 1 }; n * fat(n - 1) }; fat(25)", want: "15511210043330985984000000", wantType: object.BIGINT_OBJ},
+		{name: "big hash keys iterate in numeric order", input: `owo ks :=: ""; for k in {2 ^ 64: 1, 3: 1, -(2 ^ 64): 1} { ks += " ${k}" }; ks`, want: " -18446744073709551616 3 18446744073709551616", wantType: object.STRING},
+		{name: "big hash keys", input: `owo h :=: {2 ^ 64: "grande", 1: "pequeno"}; [h[2 ^ 63 * 2], h[2 ^ 64 / 2 ^ 64]]`, want: "[grande, pequeno]", wantType: object.ARRAY_OBJ},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+			assert.Equal(t, tc.wantType, evaluated.Type())
+		})
+	}
+}
+
+func TestEval_Decimals(t *testing.T) {
+	type test struct {
+		name     string
+		input    string
+		want     string
+		wantType object.ObjectType
+	}
+
+	tests := []test{
+		{name: "literal keeps its scale", input: "12.50d", want: "12.50", wantType: object.DECIMAL_OBJ},
+		{name: "sum is exact", input: "0.1d + 0.2d == 0.3d", want: "true", wantType: object.BOOLEAN_OBJ},
+		{name: "sum aligns scales", input: "1.5d + 0.25d", want: "1.75", wantType: object.DECIMAL_OBJ},
+		{name: "mixed with integer", input: "19.99d * 3 - 1", want: "58.97", wantType: object.DECIMAL_OBJ},
+		{name: "integer on the left", input: "100 - 0.01d", want: "99.99", wantType: object.DECIMAL_OBJ},
+		{name: "exponent literal", input: "[1.5e3d, 25e-3d]", want: "[1500, 0.025]", wantType: object.ARRAY_OBJ},
+		{name: "negative", input: "-0.5d * 2", want: "-1.0", wantType: object.DECIMAL_OBJ},
+		{name: "exact division keeps the operands scale", input: "10.00d / 4", want: "2.50", wantType: object.DECIMAL_OBJ},
+		{name: "inexact division rounds", input: "2d / 3", want: "0.66666666666666666667", wantType: object.DECIMAL_OBJ},
+		{name: "floor division and modulo", input: "[-7.5d ~/ 2, -7.5d % 2]", want: "[-4, 0.5]", wantType: object.ARRAY_OBJ},
+		{name: "power", input: "[1.1d ^ 2, 2d ^ -2]", want: "[1.21, 0.25]", wantType: object.ARRAY_OBJ},
+		{name: "comparisons ignore trailing zeros", input: "[1.0d == 1.00d, 1.5d > 1, 2 <= 1.99d]", want: "[true, true, false]", wantType: object.ARRAY_OBJ},
+		{name: "hash keys ignore trailing zeros", input: `owo h :=: {1.50d: "ok"}; h[1.5d]`, want: "ok", wantType: object.STRING},
+		{name: "whole decimals share the key of the integer", input: `owo h :=: {1: "a", 2.00d: "c"}; h[1.0d] :=: "b"; [h[1], h[1.000d], h[2]]`, want: "[b, b, c]", wantType: object.ARRAY_OBJ},
+		{name: "a whole decimal replaces the integer key", input: `{1: "a", 1.0d: "b"}`, want: "{1.0: b}", wantType: object.HASH_OBJ},
+		{name: "huge whole decimals share the key of the big integer", input: `owo h :=: {100000000000000000000: "a"}; h[100000000000000000000.0d]`, want: "a", wantType: object.STRING},
+		{name: "float is a type mismatch", input: "1.5d + 1.5", want: "ERROR: type mismatch: DECIMAL + FLOAT", wantType: object.ERROR_OBJ},
+		{name: "division by zero", input: "1.5d / 0.0d", want: "ERROR: attempted division by zero", wantType: object.ERROR_OBJ},
+		{name: "decimal from float is the shortest", input: "decimal(0.1)", want: "0.1", wantType: object.DECIMAL_OBJ},
+		{name: "decimal from string and integer", input: `[decimal("-12.50"), decimal(3)]`, want: "[-12.50, 3]", wantType: object.ARRAY_OBJ},
+		{name: "decimal from invalid string", input: `decimal("12,50")`, want: `ERROR: cannot convert "12,50" to DECIMAL`, wantType: object.ERROR_OBJ},
+		{name: "float from decimal", input: "float(0.25d) == 0.25", want: "true", wantType: object.BOOLEAN_OBJ},
+		{name: "round uses half even by default", input: "[round(2.5d, 0), round(3.5d, 0), round(2.675d, 2)]", want: "[2, 4, 2.68]", wantType: object.ARRAY_OBJ},
+		{name: "round with a mode", input: `[round(2.5d, 0, "half_up"), round(-2.5d, 0, "half_down"), round(1.01d, 0, "ceiling"), round(-1.01d, 0, "floor")]`, want: "[3, -2, 2, -2]", wantType: object.ARRAY_OBJ},
+		{name: "round to tens", input: "round(1250d, -2)", want: "1200", wantType: object.DECIMAL_OBJ},
+		{name: "round a float", input: "round(2.5, 0, \"up\") == 3.0", want: "true", wantType: object.BOOLEAN_OBJ},
+		{name: "unknown mode", input: `round(1d, 0, "banker")`, want: `ERROR: unknown rounding mode banker`, wantType: object.ERROR_OBJ},
+		{name: "format pads and rounds", input: `[format(12.5d, 2), format(2d / 3, 4), format(7, 1)]`, want: "[12.50, 0.6667, 7.0]", wantType: object.ARRAY_OBJ},
+		{name: "rounding changes the default", input: `owo antes :=: rounding("down"); owo r :=: [round(2.9d, 0), 2d / 3]; rounding(antes); r`, want: "[2, 0.66666666666666666666]", wantType: object.ARRAY_OBJ},
+		{name: "rounding returns the mode", input: "rounding()", want: "half_even", wantType: object.STRING},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+			assert.Equal(t, tc.wantType, evaluated.Type())
+		})
+	}
+}
+
+func TestEval_RoundingModeBelongsToTheEnvironment(t *testing.T) {
+	run := func(input string, env *object.Environment) string {
+		program := parser.New(lexer.New(input)).ParseProgram()
+		return Eval(program, env).Inspect()
+	}
+
+	session := object.NewEnvironment()
+	assert.Equal(t, "half_even", run(`rounding("down")`, session))
+	assert.Equal(t, "0.66666666666666666666", run("2d / 3", session), "the mode is kept between programs of the same environment")
+
+	assert.Equal(t, "0.66666666666666666667", run("2d / 3", object.NewEnvironment()), "a new environment starts with the default mode")
+	assert.Equal(t, "half_even", testEval(t, "rounding()").Inspect())
+
+	assert.Equal(t, "0.66666666666666666666", run(`fn f() { rounding("down") }; f(); 2d / 3`, object.NewEnvironment()), "a function changes the mode of the whole program")
+
+	expression := parser.New(lexer.New("2d / 3")).ParseProgram().Statements[0]
+	assert.Equal(t, "0.66666666666666666666", Eval(expression, session).Inspect(), "nodes outside of a program use the mode of their environment")
+	assert.Equal(t, "0.66666666666666666666", Eval(expression, object.NewEnclosedEnvironment(session)).Inspect())
+}
+
+func TestEval_NullAwareOperators(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "null literal", input: "null", want: "null"},
+		{name: "null equality", input: "[null == null, 0 == null, null != false]", want: "[true, false, true]"},
+		{name: "null is falsy", input: "if null { 1 } else { 2 }", want: "2"},
+		{name: "coalesce keeps a present value", input: "0 ?? 10", want: "0"},
+		{name: "coalesce replaces null", input: "null ?? 10", want: "10"},
+		{name: "coalesce replaces a missing key", input: `{"a": 1}["b"] ?? "padrão"`, want: "padrão"},
+		{name: "coalesce keeps false", input: "false ?? true", want: "false"},
+		{name: "coalesce skips the right side", input: "owo n :=: 0; owo r :=: 1 ?? (n :=: 5); [r, n]", want: "[1, 0]"},
+		{name: "coalesce chains", input: "null ?? null ?? 3", want: "3"},
+		{name: "optional field on null", input: "owo pessoa :=: null; pessoa?.nome", want: "null"},
+		{name: "optional field on a hash", input: `owo pessoa :=: {"nome": "Zooey"}; pessoa?.nome`, want: "Zooey"},
+		{name: "optional index on null skips the index", input: "owo n :=: 0; owo lista :=: null; owo r :=: lista?[n :=: 1]; [r, n]", want: "[null, 0]"},
+		{name: "optional index on an array", input: "owo lista :=: [1, 2]; [lista?[1], lista?[5]]", want: "[2, null]"},
+		{name: "optional chain with default", input: `owo cfg :=: {"db": null}; cfg?.db?.porta ?? 5432`, want: "5432"},
+		{name: "null short-circuits the rest of the chain", input: `owo n :=: 0; owo p :=: null; owo r :=: p?.endereco.rua[n :=: 1].nome; [r, n]`, want: "[null, 0]"},
+		{name: "optional index short-circuits the rest of the chain", input: "owo lista :=: null; lista?[0].nome[1]", want: "null"},
+		{name: "a null field after a present value still fails", input: `owo p :=: {"endereco": null}; p?.endereco.rua`, want: "ERROR: field access not supported: NULL"},
+		{name: "plain access on null still fails", input: "owo pessoa :=: null; pessoa.nome", want: "ERROR: field access not supported: NULL"},
+		{name: "optional access on other types still fails", input: "owo n :=: 1; n?.nome", want: "ERROR: field access not supported: INTEGER"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEval(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_MatchExpression(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	classifica := `fn classifica(v) {
+		match v {
+			0 => "zero",
+			-1 => "menos um",
+			1..9 => "digito",
+			n: int if n > 100 => "grande",
+			_: int => "inteiro",
+			0.0..1.0 => "fração",
+			"" => "vazio",
+			s: string => "texto ${s}",
+			[] => "lista vazia",
+			[x] => "um: ${x}",
+			[primeiro, ...resto] => "primeiro ${primeiro}, resto ${resto}",
+			{"tipo": "circulo", "raio": r} => "circulo ${r}",
+			{nome, idade: anos: int} => "${nome} tem ${anos}",
+			null => "nada",
+			_ => "outro",
+		}
+	}; `
+
+	tests := []test{
+		{name: "literal", input: classifica + "classifica(0)", want: "zero"},
+		{name: "negative literal", input: classifica + "classifica(-1)", want: "menos um"},
+		{name: "range is inclusive", input: classifica + "[classifica(1), classifica(9)]", want: "[digito, digito]"},
+		{name: "type pattern with guard", input: classifica + "[classifica(101), classifica(100)]", want: "[grande, inteiro]"},
+		{name: "big integers are int", input: classifica + "classifica(2 ^ 70)", want: "grande"},
+		{name: "float range", input: classifica + "classifica(0.5)", want: "fração"},
+		{name: "string literal compares by value", input: classifica + `classifica("")`, want: "vazio"},
+		{name: "string binding", input: classifica + `classifica("oi")`, want: "texto oi"},
+		{name: "empty array", input: classifica + "classifica([])", want: "lista vazia"},
+		{name: "single element array", input: classifica + "classifica([7])", want: "um: 7"},
+		{name: "array with rest", input: classifica + "classifica([1, 2, 3])", want: "primeiro 1, resto [2, 3]"},
+		{name: "hash with literal value", input: classifica + `classifica({"tipo": "circulo", "raio": 2})`, want: "circulo 2"},
+		{name: "hash with shorthand keys", input: classifica + `classifica({"nome": "Zooey", "idade": 3, "extra": true})`, want: "Zooey tem 3"},
+		{name: "hash shorthand with wrong type", input: classifica + `classifica({"nome": "Zooey", "idade": "3"})`, want: "outro"},
+		{name: "null", input: classifica + "classifica(null)", want: "nada"},
+		{name: "wildcard", input: classifica + "classifica(true)", want: "outro"},
+		{name: "rest in the middle", input: "match [1, 2, 3, 4] { [a, ...meio, b] => [a, meio, b] }", want: "[1, [2, 3], 4]"},
+		{name: "decimal equals integer", input: "match 2.00d { 2 => true, _ => false }", want: "true"},
+		{name: "block arm", input: "owo x :=: 5; match x { n if n > 3 => { owo dobro :=: n * 2; dobro + 1 } _ => 0 }", want: "11"},
+		{name: "guard sees the bindings", input: "match [3, 4] { [a, b] if a > b => a, [a, b] => b }", want: "4"},
+		{name: "return from inside an arm", input: "fn f(x) { match x { 1 => { return \"um\" } _ => 0 }; \"depois\" }; [f(1), f(2)]", want: "[um, depois]"},
+		{name: "failed arms do not leak bindings", input: "owo x :=: 0; match [1, 2] { [x, 5] => x, _ => x }", want: "0"},
+		{name: "no arm matches", input: `match "zooey" { 1 => 1 }`, want: "ERROR: no match arm matches zooey"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_Destructuring(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "array", input: "owo [nota, peso] :=: [8, 2]; nota * peso", want: "16"},
+		{name: "array with rest", input: "owo [a, b, ...resto] :=: [1, 2, 3, 4]; [a, b, resto]", want: "[1, 2, [3, 4]]"},
+		{name: "empty rest", input: "owo [a, ...resto] :=: [1]; resto", want: "[]"},
+		{name: "rest first", input: "owo [...inicio, ultimo] :=: [1, 2, 3]; [inicio, ultimo]", want: "[[1, 2], 3]"},
+		{name: "hash shorthand and rename", input: `owo {nome, idade: anos} :=: {"nome": "Zooey", "idade": 3}; "${nome} ${anos}"`, want: "Zooey 3"},
+		{name: "hash with string and integer keys", input: `owo {"a b": x, 1: y} :=: {"a b": 1, 1: 2}; x + y`, want: "3"},
+		{name: "nested", input: `owo {ponto: [x, y], tags: [primeira, ..._]} :=: {"ponto": [3, 4], "tags": ["a", "b"]}; "${x},${y} ${primeira}"`, want: "3,4 a"},
+		{name: "array defaults", input: "owo [a, b = 10, c = a + b] :=: [1]; [a, b, c]", want: "[1, 10, 11]"},
+		{name: "hash defaults", input: `owo {nome, idade = 18, cidade: c = "?"} :=: {"nome": "Zooey", "cidade": null}; [nome, idade, c]`, want: "[Zooey, 18, ?]"},
+		{name: "defaults are lazy", input: "owo n :=: 0; owo [a = (n :=: 1)] :=: [5]; [a, n]", want: "[5, 0]"},
+		{name: "wildcards skip values", input: "owo [_, segundo, _] :=: [1, 2, 3]; segundo", want: "2"},
+		{name: "type assertions", input: "owo [n: int, s: string] :=: [1, \"a\"]; [n, s]", want: "[1, a]"},
+		{name: "function result", input: "fn divmod(a, b) { [a ~/ b, a % b] }; owo [q, r] :=: divmod(7, 2); [q, r]", want: "[3, 1]"},
+		{name: "inside blocks", input: "owo total :=: 0; for par in [[1, 2], [3, 4]] { owo [a, b] :=: par; total += a * b }; total", want: "14"},
+		{name: "array parameter", input: "fn soma([a, b]) { a + b }; soma([2, 3])", want: "5"},
+		{name: "hash parameter with default", input: `fn saudacao({nome, titulo = "Dr."}) { "${titulo} ${nome}" }; [saudacao({"nome": "Zooey"}), saudacao({"nome": "Bia", "titulo": "Sra."})]`, want: "[Dr. Zooey, Sra. Bia]"},
+		{name: "too few elements", input: "owo [a, b] :=: [1]", want: "ERROR: cannot destructure [a, b]: expected 2 elements, got 1"},
+		{name: "too many elements", input: "owo [a, b] :=: [1, 2, 3]", want: "ERROR: cannot destructure [a, b]: expected 2 elements, got 3"},
+		{name: "too few elements with defaults", input: "owo [a, b, c = 0] :=: []", want: "ERROR: cannot destructure [a, b, c = 0]: expected 2 to 3 elements, got 0"},
+		{name: "too few elements with rest", input: "owo [a, b, ...c] :=: [1]", want: "ERROR: cannot destructure [a, b, ...c]: expected at least 2 elements, got 1"},
+		{name: "not an array", input: `owo [a] :=: "a"`, want: "ERROR: cannot destructure [a]: expected an array, got STRING"},
+		{name: "not a hash", input: "owo {a} :=: [1]", want: `ERROR: cannot destructure {"a": a}: expected a hash, got ARRAY`},
+		{name: "missing key", input: `owo {nome, idade} :=: {"nome": "Zooey"}`, want: `ERROR: cannot destructure {"nome": nome, "idade": idade}: missing key "idade"`},
+		{name: "nested mismatch", input: "owo [a, [b, c]] :=: [1, 2]", want: "ERROR: cannot destructure [a, [b, c]]: expected an array, got INTEGER"},
+		{name: "type mismatch", input: `owo [n: int] :=: ["1"]`, want: "ERROR: cannot destructure [n: int]: expected int, got STRING"},
+		{name: "parameter mismatch", input: "fn soma([a, b]) { a + b }; soma(1)", want: "ERROR: cannot destructure [a, b]: expected an array, got INTEGER"},
+		{name: "error in a default", input: "owo [a = 1 / 0] :=: []", want: "ERROR: attempted division by zero"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_FunctionArguments(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "default used", input: "fn f(x, y = 10) { x + y }; f(1)", want: "11"},
+		{name: "default replaced", input: "fn f(x, y = 10) { x + y }; f(1, 2)", want: "3"},
+		{name: "default sees earlier parameters", input: "fn f(x, y = x * 2) { [x, y] }; f(3)", want: "[3, 6]"},
+		{name: "default sees the closure", input: "owo base :=: 100; fn f(x = base) { x }; f()", want: "100"},
+		{name: "default evaluated on each call", input: "owo n :=: 0; fn proximo() { n += 1; n }; fn f(x = proximo()) { x }; [f(), f(), f(10), n]", want: "[1, 2, 10, 2]"},
+		{name: "rest parameter", input: "fn f(primeiro, ...resto) { [primeiro, resto] }; f(1, 2, 3)", want: "[1, [2, 3]]"},
+		{name: "empty rest parameter", input: "fn f(...xs) { xs }; f()", want: "[]"},
+		{name: "rest after defaults", input: "fn f(a, b = 2, ...c) { [a, b, c] }; [f(1), f(1, 5, 6, 7)]", want: "[[1, 2, []], [1, 5, [6, 7]]]"},
+		{name: "unnamed rest ignores extra arguments", input: "fn f(x, ...) { x }; f(1, 2, 3)", want: "1"},
+		{name: "named arguments", input: "fn f(x, y) { x - y }; f(y: 2, x: 10)", want: "8"},
+		{name: "named after positional", input: "fn f(x, y = 1, z = 2) { [x, y, z] }; f(0, z: 5)", want: "[0, 1, 5]"},
+		{name: "named typed parameter", input: "fn f(n: int) { n }; f(n: 3)", want: "3"},
+		{name: "named arguments with rest", input: "fn f(x, y = 0, ...r) { [x, y, r] }; f(1, y: 2)", want: "[1, 2, []]"},
+		{name: "arguments are evaluated in order", input: `owo log :=: ""; fn f(a, b) { log }; f(b: (log += "b"), a: (log += "a"))`, want: "ba"},
+		{name: "too few arguments", input: "fn soma(a, b) { a + b }; soma(1)", want: "ERROR: wrong number of arguments to soma: expected 2, got 1"},
+		{name: "too many arguments", input: "fn soma(a, b) { a + b }; soma(1, 2, 3)", want: "ERROR: wrong number of arguments to soma: expected 2, got 3"},
+		{name: "too many arguments with defaults", input: "fn f(a, b = 1) { a }; f(1, 2, 3)", want: "ERROR: wrong number of arguments to f: expected 1 to 2, got 3"},
+		{name: "too few arguments with rest", input: "fn f(a, b, ...c) { a }; f(1)", want: "ERROR: wrong number of arguments to f: expected at least 2, got 1"},
+		{name: "anonymous function", input: "fn(x) { x }()", want: "ERROR: wrong number of arguments to anonymous function: expected 1, got 0"},
+		{name: "unknown named argument", input: "fn f(x) { x }; f(1, z: 2)", want: "ERROR: f has no parameter named z"},
+		{name: "rest cannot be named", input: "fn f(...xs) { xs }; f(xs: [1])", want: "ERROR: f has no parameter named xs"},
+		{name: "named argument repeats a positional one", input: "fn f(x, y) { x }; f(1, x: 2)", want: "ERROR: f got more than one value for parameter x"},
+		{name: "missing named argument", input: "fn f(x, y) { x }; f(y: 2)", want: "ERROR: missing argument for parameter x of f"},
+		{name: "named arguments to a builtin", input: "len(x: [1])", want: "ERROR: builtin functions do not accept named arguments, got x"},
+		{name: "error in an argument", input: "fn f(x, y) { x }; f(1, y: 1 / 0)", want: "ERROR: attempted division by zero"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_Spread(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "call", input: "fn soma(a, b, c) { a + b + c }; owo xs :=: [1, 2, 3]; soma(...xs)", want: "6"},
+		{name: "call mixed with positional arguments", input: "fn f(a, b, c, d) { [a, b, c, d] }; f(0, ...[1, 2], 3)", want: "[0, 1, 2, 3]"},
+		{name: "call into a rest parameter", input: "fn f(x, ...resto) { resto }; f(...[1, 2, 3])", want: "[2, 3]"},
+		{name: "call with named arguments", input: "fn f(a, b = 0, c = 0) { [a, b, c] }; f(...[1], c: 3)", want: "[1, 0, 3]"},
+		{name: "call checks the arity", input: "fn f(a) { a }; f(...[1, 2])", want: "ERROR: wrong number of arguments to f: expected 1, got 2"},
+		{name: "builtin call", input: `len(...["abc"])`, want: "3"},
+		{name: "empty spread into a builtin", input: "show(...[])", want: "ERROR: wrong number of arguments to show: expected 1, got 0"},
+		{name: "too many values spread into a builtin", input: "len(...[[1], [2]])", want: "ERROR: wrong number of arguments to len: expected 1, got 2"},
+		{name: "spread into a builtin with optional arguments", input: "round(...[1.25d])", want: "ERROR: wrong number of arguments to round: expected 2 to 3, got 1"},
+		{name: "array", input: "owo a :=: [1, 2]; owo b :=: [4]; [...a, 3, ...b]", want: "[1, 2, 3, 4]"},
+		{name: "array of nothing", input: "[...[], ...[]]", want: "[]"},
+		{name: "array copies", input: "owo a :=: [1]; owo b :=: [...a]; b[0] :=: 2; [a, b]", want: "[[1], [2]]"},
+		{name: "string", input: `[..."oi"]`, want: "[o, i]"},
+		{name: "hash keys", input: `[...{"b": 2, "a": 1}]`, want: "[a, b]"},
+		{name: "hash", input: `owo padrao :=: {"cor": "azul", "tamanho": 1}; owo h :=: {...padrao, "tamanho": 2}; [h["cor"], h["tamanho"], [...h]]`, want: "[azul, 2, [cor, tamanho]]"},
+		{name: "hash written keys before spread are replaced", input: `owo h :=: {"tamanho": 2, ...{"tamanho": 1}}; h["tamanho"]`, want: "1"},
+		{name: "hash later keys win", input: `owo h :=: {"a": 1, "a": 2}; h["a"]`, want: "2"},
+		{name: "hash copies", input: `owo a :=: {"k": 1}; owo b :=: {...a}; b["k"] :=: 2; [a["k"], b["k"]]`, want: "[1, 2]"},
+		{name: "non iterable in a call", input: "fn f(x) { x }; f(...1)", want: "ERROR: cannot spread INTEGER: expected an array, a tuple, a string or a hash"},
+		{name: "non iterable in an array", input: "[...null]", want: "ERROR: cannot spread NULL: expected an array, a tuple, a string or a hash"},
+		{name: "non hash in a hash", input: "{...[1, 2]}", want: "ERROR: cannot spread ARRAY into a hash: expected a hash"},
+		{name: "error in a spread value", input: "[...(1 / 0)]", want: "ERROR: attempted division by zero"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_OwOExpression(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	funcoes := "fn aprovado(n) { n >= 6 }; " +
+		"fn filtrar(xs, ok) { owo r :=: []; for x in xs { if ok(x) { r :=: [...r, x] } }; r }; " +
+		"fn soma(xs) { owo t :=: 0; for x in xs { t += x }; t }; " +
+		"fn media(xs) { soma(xs) / len(xs) }; "
+
+	tests := []test{
+		{name: "call without arguments", input: "fn dobro(x) { x * 2 }; 4 |> dobro", want: "8"},
+		{name: "value becomes the first argument", input: "fn sub(a, b) { a - b }; 10 |> sub(3)", want: "7"},
+		{name: "chain", input: funcoes + "[4, 8, 6, 10] |> filtrar(aprovado) |> media", want: "8"},
+		{name: "left associative", input: "fn sub(a, b) { a - b }; 10 |> sub(3) |> sub(2)", want: "5"},
+		{name: "anonymous function", input: "3 |> fn(n) { n * n }", want: "9"},
+		{name: "builtin", input: `"zooey" |> len`, want: "5"},
+		{name: "named arguments", input: "fn f(x, y = 0, z = 0) { [x, y, z] }; 1 |> f(z: 3)", want: "[1, 0, 3]"},
+		{name: "spread arguments", input: "fn f(x, ...resto) { [x, resto] }; 1 |> f(...[2, 3])", want: "[1, [2, 3]]"},
+		{name: "call that returns a function", input: "fn somador(n) { fn(x) { x + n } }; 1 |> somador(2)", want: "ERROR: wrong number of arguments to somador: expected 1, got 2"},
+		{name: "function returned by a call", input: "fn somador(n) { fn(x) { x + n } }; owo mais2 :=: somador(2); 1 |> mais2", want: "3"},
+		{name: "precedence with arithmetic", input: "fn dobro(x) { x * 2 }; 1 + 2 |> dobro", want: "6"},
+		{name: "null flows through", input: "fn ou(x, padrao) { x ?? padrao }; null |> ou(1)", want: "1"},
+		{name: "not a function", input: "1 |> 2", want: "ERROR: Not a function: INTEGER"},
+		{name: "error on the left", input: "fn f(x) { x }; (1 / 0) |> f", want: "ERROR: attempted division by zero"},
+		{name: "arity is checked", input: "fn f(x, y) { x }; 1 |> f", want: "ERROR: wrong number of arguments to f: expected 2, got 1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
+
+func TestEval_Tuples(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	divmod := "fn divmod(a, b) { return a ~/ b, a % b }; "
+
+	tests := []test{
+		{name: "return a tuple", input: divmod + "divmod(7, 2)", want: "(3, 1)"},
+		{name: "destructure", input: divmod + "owo q, r :=: divmod(7, 2); q * 2 + r", want: "7"},
+		{name: "destructure with patterns", input: `fn f() { return "zooey", [1, 2], {"idade": 3} }; owo nome, [_, b], {idade} :=: f(); "${nome} ${b} ${idade}"`, want: "zooey 2 3"},
+		{name: "destructure with a default", input: "fn f() { return 1, null }; owo a, b = 5 :=: f(); a + b", want: "6"},
+		{name: "array patterns destructure tuples", input: divmod + "owo [q, r] :=: divmod(7, 2); owo [x, ...resto] :=: (1, 2, 3); [q, r, x, resto]", want: "[3, 1, 1, [2, 3]]"},
+		{name: "array patterns match tuples", input: "match (1, 2) { [a, b, c] => 0, [a, b] => a + b }", want: "3"},
+		{name: "destructure with rest", input: "fn f() { return 1, 2, 3 }; owo a, ...resto :=: f(); [a, resto]", want: "[1, [2, 3]]"},
+		{name: "index", input: divmod + "owo t :=: divmod(7, 2); [t[0], t[1], t[2]]", want: "[3, 1, null]"},
+		{name: "len", input: divmod + "len(divmod(7, 2))", want: "2"},
+		{name: "spread", input: divmod + "[...divmod(7, 2), 0]", want: "[3, 1, 0]"},
+		{name: "for in", input: divmod + "owo s :=: 0; for i, v in divmod(7, 2) { s += i + v }; s", want: "5"},
+		{name: "literal", input: "owo x :=: 2; (1, x + 1, [3])", want: "(1, 3, [3])"},
+		{name: "parentheses around one value are not a tuple", input: "(1 + 2) * 2", want: "6"},
+		{name: "nested tuples", input: "((1, 2), \"x\")", want: "((1, 2), x)"},
+		{name: "equal", input: "[(1, \"a\") == (1, \"a\"), (1, 2) == (2, 1), (1, 2) != (1, 2)]", want: "[true, false, false]"},
+		{name: "equal across number types", input: "(1, 2.0) == (1.0, 2)", want: "true"},
+		{name: "ordering", input: "[(1, \"b\") < (1, \"c\"), (2, 0) > (1, 9), (1, 2) <= (1, 2), (0, 1) >= (0, 2)]", want: "[true, true, true, false]"},
+		{name: "shorter tuple is smaller", input: "(1, 2) < (1, 2, 0)", want: "true"},
+		{name: "incomparable elements", input: "(1, 2) < (\"1\", 2)", want: "ERROR: cannot compare tuples: elements at index 0 are INTEGER and STRING"},
+		{name: "hash key", input: "owo h :=: {(0, 0): \"origem\"}; h[(1, 1)] :=: \"diagonal\"; [h[(0, 0)], h[(1, 1)], h[(0, 1)]]", want: "[origem, diagonal, null]"},
+		{name: "hash key element order matters", input: "owo h :=: {(1, \"1\"): 1}; h[(\"1\", 1)]", want: "null"},
+		{name: "unhashable tuple", input: "{(1, [2]): 1}", want: "ERROR: Unusable as hash key: TUPLE"},
+		{name: "unhashable nested tuple", input: "owo h :=: {}; h[(1, (2, {}))] :=: 1", want: "ERROR: unusable as hash key: TUPLE"},
+		{name: "unhashable tuple lookup", input: "{(1, 2): 1}[([1], 2)]", want: "ERROR: unusable as hash key: TUPLE"},
+		{name: "immutable", input: "owo t :=: (1, 2); t[0] :=: 5", want: "ERROR: cannot assign to an element of a tuple, tuples are immutable"},
+		{name: "match on tuples", input: divmod + "match divmod(7, 2) { t: tuple => t[0], _ => 0 }", want: "3"},
+		{name: "destructure a non tuple", input: "owo a, b :=: [1, 2]", want: "ERROR: cannot destructure a, b: expected a tuple, got ARRAY"},
+		{name: "destructure the wrong size", input: divmod + "owo a, b, c :=: divmod(7, 2)", want: "ERROR: cannot destructure a, b, c: expected 3 elements, got 2"},
+		{name: "error in an element", input: "fn f() { return 1, 1 / 0 }; f()", want: "ERROR: attempted division by zero"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated := testEvalResolved(t, tc.input)
+
+			assert.Equal(t, tc.want, evaluated.Inspect())
+		})
+	}
+}
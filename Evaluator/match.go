@@ -0,0 +1,43 @@
+package evaluator
+
+import (
+	ast "github.com/ZooeyLang/AST"
+	object "github.com/ZooeyLang/Object"
+)
+
+// Testa os braços em ordem. Cada braço ganha um environment próprio com as variáveis do seu padrão,
+// onde a guarda e o corpo são avaliados; um braço que não casa é descartado junto com o seu environment
+func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(me.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+	if subject == nil {
+		subject = NULL
+	}
+
+	for _, arm := range me.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+		mismatch, err := matchPattern(arm.Pattern, subject, armEnv)
+		if err != nil {
+			return err
+		}
+		if mismatch != "" {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, armEnv)
+	}
+
+	return newError("no match arm matches %s", subject.Inspect())
+}
@@ -0,0 +1,250 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+
+	ast "github.com/ZooeyLang/AST"
+	object "github.com/ZooeyLang/Object"
+)
+
+// Os padrões são usados pelo match, que testa um braço depois do outro, e pelas declarações
+// owo [a, b] :=: ... e parâmetros fn f({nome}), onde um valor que não casa é um erro
+
+// Os objetos aceitos por cada tipo de um padrão como n: int
+var patternTypes = map[string][]object.ObjectType{
+	"array":   {object.ARRAY_OBJ},
+	"bool":    {object.BOOLEAN_OBJ},
+	"decimal": {object.DECIMAL_OBJ},
+	"float":   {object.FLOAT},
+	"fn":      {object.FUNCTION_OBJ, object.BUILTIN_OBJ},
+	"hash":    {object.HASH_OBJ},
+	"int":     {object.INTEGER_OBJ, object.BIGINT_OBJ},
+	"null":    {object.NULL_OBJ},
+	"string":  {object.STRING},
+	"tuple":   {object.TUPLE_OBJ},
+}
+
+// Declara em env as variáveis do padrão, ou retorna um erro quando o valor não tem a forma dele
+func destructure(pattern ast.Pattern, value object.Object, env *object.Environment) object.Object {
+	if value == nil {
+		value = NULL
+	}
+
+	mismatch, err := matchPattern(pattern, value, env)
+	if err != nil {
+		return err
+	}
+	if mismatch != "" {
+		return newError("cannot destructure %s: %s", pattern.String(), mismatch)
+	}
+	return nil
+}
+
+// Confere se value tem a forma do padrão, declarando em env as variáveis do padrão.
+// Retorna o motivo quando o valor não casa, ou "" quando casa; err é o erro de um valor padrão
+func matchPattern(pattern ast.Pattern, value object.Object, env *object.Environment) (string, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return "", nil
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, value)
+		return "", nil
+	case *ast.TypePattern:
+		if !hasPatternType(value, pattern.Type.Literal) {
+			return fmt.Sprintf("expected %s, got %s", pattern.Type.Literal, value.Type()), nil
+		}
+		if pattern.Name != nil {
+			env.Set(pattern.Name.Value, value)
+		}
+		return "", nil
+	case *ast.DefaultPattern:
+		// O valor padrão só é avaliado quando falta o valor
+		if isNull(value) {
+			value = Eval(pattern.Default, env)
+			if isError(value) {
+				return "", value
+			}
+			if value == nil {
+				value = NULL
+			}
+		}
+		return matchPattern(pattern.Pattern, value, env)
+	case *ast.LiteralPattern:
+		if !valuesEqual(Eval(pattern.Value, env), value, env) {
+			return fmt.Sprintf("expected %s, got %s", pattern.String(), describeValue(value)), nil
+		}
+		return "", nil
+	case *ast.RangePattern:
+		low, ok := compareValues(Eval(pattern.Low, env), value, env)
+		if ok && low <= 0 {
+			high, ok := compareValues(value, Eval(pattern.High, env), env)
+			if ok && high <= 0 {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("%s is not in %s", describeValue(value), pattern.String()), nil
+	case *ast.ArrayPattern:
+		// Como no spread e no for-in, uma tupla também serve onde se espera um array
+		switch value := value.(type) {
+		case *object.Array:
+			return matchElements(pattern.Elements, value.Elements, env)
+		case *object.Tuple:
+			return matchElements(pattern.Elements, value.Elements, env)
+		}
+		return fmt.Sprintf("expected an array, got %s", value.Type()), nil
+	case *ast.TuplePattern:
+		tuple, ok := value.(*object.Tuple)
+		if !ok {
+			return fmt.Sprintf("expected a tuple, got %s", value.Type()), nil
+		}
+		return matchElements(pattern.Elements, tuple.Elements, env)
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, value, env)
+	}
+	return fmt.Sprintf("unsupported pattern %s", pattern.String()), nil
+}
+
+// Os elementos de um array ou de uma tupla
+func matchElements(elementPatterns []ast.Pattern, elements []object.Object, env *object.Environment) (string, object.Object) {
+	// Os padrões antes do resto casam com o começo do array e os de depois com o final
+	patterns, rest := []ast.Pattern{}, -1
+	for i, element := range elementPatterns {
+		if _, ok := element.(*ast.RestPattern); ok {
+			rest = i
+			continue
+		}
+		patterns = append(patterns, element)
+	}
+
+	// Os elementos com valor padrão podem faltar, desde que venham depois de todos os obrigatórios
+	required := 0
+	for i, element := range patterns {
+		if _, ok := element.(*ast.DefaultPattern); !ok {
+			required = i + 1
+		}
+	}
+
+	switch {
+	case rest >= 0 && len(elements) < required:
+		return fmt.Sprintf("expected at least %d elements, got %d", required, len(elements)), nil
+	case rest < 0 && (len(elements) < required || len(elements) > len(patterns)):
+		if required == len(patterns) {
+			return fmt.Sprintf("expected %d elements, got %d", required, len(elements)), nil
+		}
+		return fmt.Sprintf("expected %d to %d elements, got %d", required, len(patterns), len(elements)), nil
+	}
+
+	values, middle := elements, []object.Object{}
+	if rest >= 0 && len(elements) >= len(patterns) {
+		before, after := rest, len(patterns)-rest
+		values = append(append([]object.Object{}, elements[:before]...), elements[len(elements)-after:]...)
+		middle = append(middle, elements[before:len(elements)-after]...)
+	}
+
+	for i, element := range patterns {
+		value := object.Object(NULL)
+		if i < len(values) {
+			value = values[i]
+		}
+		if mismatch, err := matchPattern(element, value, env); mismatch != "" || err != nil {
+			return mismatch, err
+		}
+	}
+
+	if rest >= 0 {
+		if name := elementPatterns[rest].(*ast.RestPattern).Name; name != nil {
+			env.Set(name.Value, &object.Array{Elements: middle})
+		}
+	}
+	return "", nil
+}
+
+// Todas as chaves do padrão precisam existir no hash, a não ser as que têm valor padrão.
+// As outras chaves do hash são ignoradas
+func matchHashPattern(pattern *ast.HashPattern, value object.Object, env *object.Environment) (string, object.Object) {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return fmt.Sprintf("expected a hash, got %s", value.Type()), nil
+	}
+
+	for i, key := range pattern.Keys {
+		keyObj := Eval(key, env)
+		hashed, ok := hashKey(keyObj)
+		if !ok {
+			return fmt.Sprintf("unusable as hash key: %s", keyObj.Type()), nil
+		}
+
+		value := object.Object(NULL)
+		if pair, ok := hash.Pairs[hashed]; ok {
+			value = pair.Value
+		} else if _, ok := pattern.Values[i].(*ast.DefaultPattern); !ok {
+			return fmt.Sprintf("missing key %s", describeValue(keyObj)), nil
+		}
+
+		if mismatch, err := matchPattern(pattern.Values[i], value, env); mismatch != "" || err != nil {
+			return mismatch, err
+		}
+	}
+	return "", nil
+}
+
+func hasPatternType(value object.Object, name string) bool {
+	for _, t := range patternTypes[name] {
+		if value.Type() == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Strings aparecem entre aspas nas mensagens, para que "1" e 1 não fiquem iguais
+func describeValue(value object.Object) string {
+	if str, ok := value.(*object.String); ok {
+		return fmt.Sprintf("%q", str.Value)
+	}
+	return value.Inspect()
+}
+
+func isNumber(obj object.Object) bool {
+	return isInteger(obj) || obj.Type() == object.FLOAT || obj.Type() == object.DECIMAL_OBJ
+}
+
+// Igualdade de valores, e não de objetos: o "a" do padrão é outro objeto, mas é igual ao "a" do valor
+func valuesEqual(left, right object.Object, env *object.Environment) bool {
+	if left.Type() == object.STRING && right.Type() == object.STRING {
+		return left.(*object.String).Value == right.(*object.String).Value
+	}
+	if isNumber(left) && isNumber(right) {
+		return evalInfixExpression("==", left, right, env) == TRUE
+	}
+	if left.Type() == object.TUPLE_OBJ && right.Type() == object.TUPLE_OBJ {
+		return tuplesEqual(left.(*object.Tuple), right.(*object.Tuple), env)
+	}
+	// true, false e null são sempre os mesmos objetos
+	return left == right
+}
+
+// Ordena dois números ou duas strings. ok é false quando os valores não podem ser comparados,
+// como um número e uma string, ou um Decimal e um Float
+func compareValues(left, right object.Object, env *object.Environment) (int, bool) {
+	if left.Type() == object.STRING && right.Type() == object.STRING {
+		return strings.Compare(left.(*object.String).Value, right.(*object.String).Value), true
+	}
+	if left.Type() == object.TUPLE_OBJ && right.Type() == object.TUPLE_OBJ {
+		return compareTuples(left.(*object.Tuple), right.(*object.Tuple), env)
+	}
+	if !isNumber(left) || !isNumber(right) {
+		return 0, false
+	}
+
+	switch {
+	case evalInfixExpression("<", left, right, env) == TRUE:
+		return -1, true
+	case evalInfixExpression(">", left, right, env) == TRUE:
+		return 1, true
+	case evalInfixExpression("==", left, right, env) == TRUE:
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+package evaluator
+
+import (
+	ast "github.com/ZooeyLang/AST"
+	object "github.com/ZooeyLang/Object"
+)
+
+// Os valores que um ...espalhamento coloca numa chamada ou num array, na mesma ordem do for in:
+// os elementos de um array ou tupla, os caracteres de uma string e as chaves de um hash
+func spreadValues(spread *ast.SpreadExpression, env *object.Environment) ([]object.Object, object.Object) {
+	value := Eval(spread.Value, env)
+	if isError(value) {
+		return nil, value
+	}
+	if value == nil {
+		value = NULL
+	}
+
+	switch value := value.(type) {
+	case *object.Array:
+		return value.Elements, nil
+	case *object.Tuple:
+		return value.Elements, nil
+	case *object.String:
+		chars := []object.Object{}
+		for _, char := range value.Value {
+			chars = append(chars, &object.String{Value: string(char)})
+		}
+		return chars, nil
+	case *object.Hash:
+		keys := []object.Object{}
+		for _, pair := range sortedHashPairs(value) {
+			keys = append(keys, pair.Key)
+		}
+		return keys, nil
+	}
+
+	return nil, newError("cannot spread %s: expected an array, a tuple, a string or a hash", value.Type())
+}
+
+// Copia os pares do hash espalhado para pairs. Os pares escritos depois substituem os copiados, e vice-versa
+func spreadHash(spread *ast.SpreadExpression, pairs map[object.HashKey]object.HashPair, env *object.Environment) object.Object {
+	value := Eval(spread.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		if value == nil {
+			value = NULL
+		}
+		return newError("cannot spread %s into a hash: expected a hash", value.Type())
+	}
+
+	for key, pair := range hash.Pairs {
+		pairs[key] = pair
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package evaluator
+
+import (
+	object "github.com/ZooeyLang/Object"
+)
+
+// Tuplas são comparadas elemento por elemento: (1, "b") < (1, "c") e (1, 2) < (1, 2, 0)
+func evalTupleInfixExpression(operator string, left, right *object.Tuple, env *object.Environment) object.Object {
+	switch operator {
+	case "==":
+		return nativeBoolToBooleanObject(tuplesEqual(left, right, env))
+	case "!=":
+		return nativeBoolToBooleanObject(!tuplesEqual(left, right, env))
+	case "<", ">", "<=", ">=":
+		order, ok := compareTuples(left, right, env)
+		if !ok {
+			i := firstDifference(left, right, env)
+			return newError("cannot compare tuples: elements at index %d are %s and %s", i, left.Elements[i].Type(), right.Elements[i].Type())
+		}
+		switch operator {
+		case "<":
+			return nativeBoolToBooleanObject(order < 0)
+		case ">":
+			return nativeBoolToBooleanObject(order > 0)
+		case "<=":
+			return nativeBoolToBooleanObject(order <= 0)
+		default:
+			return nativeBoolToBooleanObject(order >= 0)
+		}
+	}
+	return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+func tuplesEqual(left, right *object.Tuple, env *object.Environment) bool {
+	if len(left.Elements) != len(right.Elements) {
+		return false
+	}
+	for i, element := range left.Elements {
+		if !valuesEqual(element, right.Elements[i], env) {
+			return false
+		}
+	}
+	return true
+}
+
+// A ordem é decidida pelo primeiro par de elementos diferentes; se um acaba antes, ele é o menor.
+// ok é false quando esse par não pode ser comparado
+func compareTuples(left, right *object.Tuple, env *object.Environment) (int, bool) {
+	if i := firstDifference(left, right, env); i >= 0 {
+		return compareValues(left.Elements[i], right.Elements[i], env)
+	}
+
+	switch {
+	case len(left.Elements) < len(right.Elements):
+		return -1, true
+	case len(left.Elements) > len(right.Elements):
+		return 1, true
+	}
+	return 0, true
+}
+
+// O índice do primeiro par de elementos diferentes, ou -1 quando um é o começo do outro
+func firstDifference(left, right *object.Tuple, env *object.Environment) int {
+	for i := 0; i < len(left.Elements) && i < len(right.Elements); i++ {
+		if !valuesEqual(left.Elements[i], right.Elements[i], env) {
+			return i
+		}
+	}
+	return -1
+}
+
+// A chave de obj num hash. ok é false para os valores que não podem ser chaves, como um array
+// ou uma tupla que contém um array
+func hashKey(obj object.Object) (object.HashKey, bool) {
+	hashable, ok := obj.(object.Hashable)
+	if !ok {
+		return object.HashKey{}, false
+	}
+	key := hashable.HashKey()
+	return key, key != object.HashKey{}
+}
@@ -2,45 +2,131 @@ package Lexer
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	diagnostic "github.com/ZooeyLang/Diagnostic"
 	token "github.com/ZooeyLang/Token"
 )
 
+// Códigos dos diagnósticos gerados pelo lexer
+const (
+	CodeUnterminatedComment = "L001"
+	CodeUnterminatedString  = "L002"
+	CodeInvalidEscape       = "L003"
+	CodeMalformedNumber     = "L004"
+)
+
 type Lexer struct {
 	input    string // Cadeia de caracters a ser recebida e tokenizada
 	curChar  int    //cursor para posição atual na cadeia
 	nextChar int    // posição seguinte ao cursor
-	ch       byte   // char
+	ch       rune   // char atual, já decodificado do UTF-8
+
+	filename string // Nome do arquivo, usado apenas nas posições dos tokens
+	line     int    // Linha do char atual
+	column   int    // Coluna do char atual
+
+	keepComments bool            // Se os comentários devem ser guardados nos tokens
+	comments     []token.Comment // Comentários lidos desde o último token
+
+	// Uma entrada para cada ${ aberto, com quantos { ainda estão abertos dentro dele.
+	// Um } quando o topo é zero fecha a interpolação e volta a ler a string
+	templates []int
+
+	// Tipo do último token, para decidir se um .5 é um número mal escrito ou o ponto de um acesso, como em x.5
+	last token.Type
+
+	errors []diagnostic.Diagnostic
 }
 
 func New(input string) *Lexer {
-	lexer := &Lexer{input: input} //Inicia um novo lexer com a cadeia de char passada
-	lexer.readChar()              //Coloca o cursor na posição do primeiro caracter da cadeia
+	return NewFile("", input)
+}
+
+// Cria um lexer cujos tokens carregam o nome do arquivo de origem nas suas posições
+func NewFile(filename string, input string) *Lexer {
+	lexer := &Lexer{input: input, filename: filename, line: 1} //Inicia um novo lexer com a cadeia de char passada
+	lexer.readChar()                                           //Coloca o cursor na posição do primeiro caracter da cadeia
+
+	// Alguns editores começam o arquivo com um BOM; ele não faz parte do código, nem conta como coluna
+	if lexer.ch == bom {
+		lexer.readChar()
+		lexer.column = 1
+	}
 
 	return lexer
 }
 
+// O código fonte completo que está sendo tokenizado
+func (lexer *Lexer) Source() string {
+	return lexer.input
+}
+
+// Faz os tokens guardarem os comentários que os precedem, para ferramentas como um formatador
+func (lexer *Lexer) KeepComments() {
+	lexer.keepComments = true
+}
+
+// Problemas encontrados durante a leitura, como um comentário /* que nunca é fechado
+func (lexer *Lexer) Errors() []diagnostic.Diagnostic {
+	return lexer.errors
+}
+
+func (lexer *Lexer) position() token.Position {
+	return token.Position{Filename: lexer.filename, Offset: lexer.curChar, Line: lexer.line, Column: lexer.column}
+}
+
+const bom = '\uFEFF'
+
+// Avança para o próximo caracter. Os cursores contam bytes, mas a coluna conta caracteres,
+// então "média" ocupa 6 bytes e 5 colunas
 func (lexer *Lexer) readChar() {
+	// Avança a linha/coluna a partir do char que está sendo deixado para trás
+	if lexer.ch == '\n' {
+		lexer.line++
+		lexer.column = 1
+	} else if lexer.nextChar <= len(lexer.input) {
+		lexer.column++
+	}
+
+	lexer.curChar = lexer.nextChar
+
 	//Verifica se a proxima posição é o final da cadeia, atribuindo 0 em caso positivo
 	if lexer.nextChar >= len(lexer.input) {
 		lexer.ch = 0
-	} else {
-		//Caso contrário, temos de acessar o char atual
-
-		lexer.ch = lexer.input[lexer.nextChar]
+		lexer.nextChar += 1
+		return
 	}
 
-	lexer.curChar = lexer.nextChar
-
-	lexer.nextChar += 1
+	// Um byte que não é UTF-8 válido vira utf8.RuneError com tamanho 1
+	ch, size := utf8.DecodeRuneInString(lexer.input[lexer.nextChar:])
+	lexer.ch = ch
+	lexer.nextChar += size
 }
 
-//Para cada elemento da cadeia de chars, analisamos o cursor e atribuimos um token a esse char
+// Para cada elemento da cadeia de chars, analisamos o cursor e atribuimos um token a esse char
 func (lexer *Lexer) NextToken() token.Token {
-	var tok token.Token
+	lexer.skipTrivia()
+
+	start := lexer.position()
+	tok := lexer.scanToken()
+	tok.Pos = start
+	tok.End = lexer.position()
+
+	if len(lexer.comments) != 0 {
+		tok.Comments = lexer.comments
+		lexer.comments = nil
+	}
+	lexer.last = tok.Type
+
+	return tok
+}
 
-	lexer.skipWhiteSpaces()
+func (lexer *Lexer) scanToken() token.Token {
+	var tok token.Token
 
 	switch lexer.ch {
 	case '=':
@@ -48,22 +134,52 @@ func (lexer *Lexer) NextToken() token.Token {
 			ch := lexer.ch
 			lexer.readChar()
 			tok = token.Token{Type: token.EQ, Literal: string(ch) + string(lexer.ch)}
+		} else if lexer.peekChar() == '>' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: string(ch) + string(lexer.ch)}
 		} else {
-			tok = newToken(token.ILLEGAL, lexer.ch)
+			tok = newToken(token.DEFAULT, lexer.ch)
 		}
 	case '.':
-		tok = newToken(token.DOT, lexer.ch)
+		// .5 não é um número válido, mas é quase certo que era isso que se queria escrever.
+		// Depois de um operando, como em x.5, o ponto é só um ponto e o erro fica com o parser
+		if lexer.peekChar() == '.' {
+			lexer.readChar()
+			if lexer.peekChar() == '.' {
+				lexer.readChar()
+				tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+			} else {
+				tok = token.Token{Type: token.DOTDOT, Literal: ".."}
+			}
+		} else if isDecimalDigit(lexer.peekChar()) && !endsOperand(lexer.last) {
+			return lexer.readLeadingDotNumber()
+		} else {
+			tok = newToken(token.DOT, lexer.ch)
+		}
 	case '+':
 		if lexer.peekChar() == '+' {
 			ch := lexer.ch
 			lexer.readChar()
 			tok = token.Token{Type: token.PLUSPLUS, Literal: string(ch) + string(lexer.ch)}
+		} else if lexer.peekChar() == '=' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.PLUS_ASSIGN, Literal: string(ch) + string(lexer.ch)}
 		} else {
 			tok = newToken(token.PLUS, lexer.ch)
 		}
 	case '"':
 		tok.Type = token.STRING
-		tok.Literal = lexer.readString()
+		if lexer.peekChar() == '"' && lexer.peekCharAt(1) == '"' {
+			tok.Literal = lexer.readHeredoc()
+			return tok
+		}
+		return lexer.readString(true)
+	case '`':
+		tok.Type = token.STRING
+		tok.Literal = lexer.readRawString()
+		return tok
 	case '^':
 		tok = newToken(token.POW, lexer.ch)
 	case '-':
@@ -71,6 +187,10 @@ func (lexer *Lexer) NextToken() token.Token {
 			ch := lexer.ch
 			lexer.readChar()
 			tok = token.Token{Type: token.MINUSMINUS, Literal: string(ch) + string(lexer.ch)}
+		} else if lexer.peekChar() == '=' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.MINUS_ASSIGN, Literal: string(ch) + string(lexer.ch)}
 		} else {
 			tok = newToken(token.MINUS, lexer.ch)
 		}
@@ -87,12 +207,65 @@ func (lexer *Lexer) NextToken() token.Token {
 			ch := lexer.ch
 			lexer.readChar()
 			tok = token.Token{Type: token.AND, Literal: string(ch) + string(lexer.ch)}
+		} else {
+			tok = newToken(token.AMPERSAND, lexer.ch)
+		}
+	case '|':
+		if lexer.peekChar() == '|' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.OR, Literal: string(ch) + string(lexer.ch)}
+		} else if lexer.peekChar() == '>' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.PIPELINE, Literal: string(ch) + string(lexer.ch)}
+		} else {
+			tok = newToken(token.PIPE, lexer.ch)
+		}
+	case '~':
+		if lexer.peekChar() == '/' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.FLOOR_DIV, Literal: string(ch) + string(lexer.ch)}
+		} else {
+			tok = newToken(token.TILDE, lexer.ch)
+		}
+	case '%':
+		tok = newToken(token.PERCENT, lexer.ch)
+	case '?':
+		switch lexer.peekChar() {
+		case '?':
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.NULLISH, Literal: string(ch) + string(lexer.ch)}
+		case '.':
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.OPTIONAL_DOT, Literal: string(ch) + string(lexer.ch)}
+		case '[':
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.OPTIONAL_LBRACKET, Literal: string(ch) + string(lexer.ch)}
+		default:
+			tok = newToken(token.ILLEGAL, lexer.ch)
 		}
 	case '/':
-		tok = newToken(token.SLASH, lexer.ch)
+		if lexer.peekChar() == '=' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.SLASH_ASSIGN, Literal: string(ch) + string(lexer.ch)}
+		} else {
+			tok = newToken(token.SLASH, lexer.ch)
+		}
 
 	case '*':
-		tok = newToken(token.ASTERISK, lexer.ch)
+		if lexer.peekChar() == '=' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.ASTERISK_ASSIGN, Literal: string(ch) + string(lexer.ch)}
+		} else {
+			tok = newToken(token.ASTERISK, lexer.ch)
+		}
 
 	case ':':
 		if lexer.peekChar() == '=' {
@@ -115,6 +288,10 @@ func (lexer *Lexer) NextToken() token.Token {
 			ch := lexer.ch
 			lexer.readChar()
 			tok = token.Token{Type: token.LTE, Literal: string(ch) + string(lexer.ch)}
+		} else if lexer.peekChar() == '<' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.SHL, Literal: string(ch) + string(lexer.ch)}
 		} else {
 			tok = newToken(token.LT, lexer.ch)
 		}
@@ -124,6 +301,10 @@ func (lexer *Lexer) NextToken() token.Token {
 			lexer.readChar()
 			tok = token.Token{Type: token.GTE, Literal: string(ch) + string(lexer.ch)}
 
+		} else if lexer.peekChar() == '>' {
+			ch := lexer.ch
+			lexer.readChar()
+			tok = token.Token{Type: token.SHR, Literal: string(ch) + string(lexer.ch)}
 		} else {
 			tok = newToken(token.GT, lexer.ch)
 		}
@@ -132,8 +313,18 @@ func (lexer *Lexer) NextToken() token.Token {
 	case ',':
 		tok = newToken(token.COMMA, lexer.ch)
 	case '{':
+		if n := len(lexer.templates); n > 0 {
+			lexer.templates[n-1]++
+		}
 		tok = newToken(token.LBRACE, lexer.ch)
 	case '}':
+		if n := len(lexer.templates); n > 0 {
+			if lexer.templates[n-1] == 0 {
+				lexer.templates = lexer.templates[:n-1]
+				return lexer.readString(false)
+			}
+			lexer.templates[n-1]--
+		}
 		tok = newToken(token.RBRACE, lexer.ch)
 	case '(':
 		tok = newToken(token.LPAREN, lexer.ch)
@@ -153,17 +344,11 @@ func (lexer *Lexer) NextToken() token.Token {
 			tok.Type = token.LookupIdent(tok.Literal)
 
 			return tok
-		} else if isDigit(lexer.ch) {
-			tok.Literal = lexer.readNumber()
-			if strings.Contains(tok.Literal, ".") == true {
-				tok.Type = token.FLOAT
-			} else {
-				tok.Type = token.INT
-			}
-
-			return tok
+		} else if isDecimalDigit(lexer.ch) {
+			return lexer.readNumber()
 		} else {
-			tok = newToken(token.ILLEGAL, lexer.ch)
+			// O literal é o trecho original, para que um byte inválido apareça como ele é, ex: "\xff"
+			tok = token.Token{Type: token.ILLEGAL, Literal: lexer.input[lexer.curChar:lexer.nextChar]}
 		}
 
 	}
@@ -172,80 +357,369 @@ func (lexer *Lexer) NextToken() token.Token {
 	return tok
 }
 
-func (lexer *Lexer) readString() string {
+// Ex: "nota:\t10\n". Lê até o " de fechamento, decodificando as sequências de escape.
+// Uma string comum não pode quebrar a linha; para isso existem as strings com """
+//
+// Strings com ${...} são divididas em partes: TEMPLATE_HEAD vai do " até o primeiro ${,
+// TEMPLATE_MIDDLE de um } até o próximo ${ e TEMPLATE_TAIL do último } até o ". As expressões
+// entre elas viram tokens normais. head diz se a leitura começa no " ou no } de uma interpolação
+func (lexer *Lexer) readString(head bool) token.Token {
+	start := lexer.position()
+	lexer.readChar()
+	first := lexer.curChar
 
-	position := lexer.curChar + 1
+	closed, open := token.Type(token.TEMPLATE_TAIL), token.Type(token.TEMPLATE_MIDDLE)
+	if head {
+		closed, open = token.STRING, token.TEMPLATE_HEAD
+	}
 
-	for {
+	for lexer.ch != '"' && !(lexer.ch == '$' && lexer.peekChar() == '{') {
+		if lexer.ch == 0 || lexer.ch == '\n' {
+			lexer.unterminated(start, "string literal", `"`)
+			return token.Token{Type: closed, Literal: lexer.unescape(lexer.input[first:lexer.curChar], first)}
+		}
+		// O char depois de uma \ nunca fecha a string, ex: "aspas: \""
+		if lexer.ch == '\\' && lexer.peekChar() != '\n' && lexer.peekChar() != 0 {
+			lexer.readChar()
+		}
+		lexer.readChar()
+	}
+
+	tok := token.Token{Type: closed, Literal: lexer.unescape(lexer.input[first:lexer.curChar], first)}
+
+	if lexer.ch == '$' {
+		tok.Type = open
+		lexer.templates = append(lexer.templates, 0)
 		lexer.readChar()
-		if lexer.ch == '"' || lexer.ch == 0 {
-			break
+	}
+	lexer.readChar()
+
+	return tok
+}
+
+// Ex: `C:\zooey\notas`. Strings entre crases não têm escapes e podem ter várias linhas
+func (lexer *Lexer) readRawString() string {
+	start := lexer.position()
+	lexer.readChar()
+	first := lexer.curChar
+
+	for lexer.ch != '`' {
+		if lexer.ch == 0 {
+			lexer.unterminated(start, "raw string literal", "`")
+			return lexer.input[first:lexer.curChar]
 		}
+		lexer.readChar()
 	}
 
-	return lexer.input[position:lexer.curChar]
+	value := lexer.input[first:lexer.curChar]
+	lexer.readChar()
 
+	return value
 }
 
-func (lexer *Lexer) skipWhiteSpaces() {
-	for lexer.ch == ' ' || lexer.ch == '\t' || lexer.ch == '\n' || lexer.ch == '\r' {
+// Ex:
+//
+//	owo consulta :=: """
+//	    SELECT nome
+//	      FROM alunos
+//	    """
+//
+// Strings com """ podem ter várias linhas. A quebra de linha logo depois do """ de abertura e a linha
+// do """ de fechamento são descartadas, e a indentação comum a todas as linhas é removida,
+// então o exemplo vira "SELECT nome\n  FROM alunos". As sequências de escape funcionam normalmente
+func (lexer *Lexer) readHeredoc() string {
+	start := lexer.position()
+	lexer.readChar()
+	lexer.readChar()
+	lexer.readChar()
+	first := lexer.curChar
+
+	for !(lexer.ch == '"' && lexer.peekChar() == '"' && lexer.peekCharAt(1) == '"') {
+		if lexer.ch == 0 {
+			lexer.unterminated(start, "multi-line string literal", `"""`)
+			return lexer.dedent(first, lexer.curChar)
+		}
+		if lexer.ch == '\\' && lexer.peekChar() != 0 {
+			lexer.readChar()
+		}
 		lexer.readChar()
 	}
+
+	value := lexer.dedent(first, lexer.curChar)
+	lexer.readChar()
+	lexer.readChar()
+	lexer.readChar()
+
+	return value
 }
 
-func newToken(tokenType token.Type, ch byte) token.Token {
-	return token.Token{Type: tokenType, Literal: string(ch)}
+// Remove a indentação comum das linhas de input[first:last] e decodifica os escapes de cada linha
+func (lexer *Lexer) dedent(first, last int) string {
+	type line struct {
+		text   string
+		offset int
+	}
+
+	lines := []line{}
+	offset := first
+	for _, text := range strings.Split(lexer.input[first:last], "\n") {
+		lines = append(lines, line{text: strings.TrimRight(text, "\r"), offset: offset})
+		offset += len(text) + 1
+	}
+
+	if strings.TrimSpace(lines[0].text) == "" && len(lines) > 1 {
+		lines = lines[1:]
+	}
+	if strings.TrimSpace(lines[len(lines)-1].text) == "" && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := ""
+	found := false
+	for _, l := range lines {
+		if strings.TrimSpace(l.text) == "" {
+			continue
+		}
+
+		leading := l.text[:len(l.text)-len(strings.TrimLeft(l.text, " \t"))]
+		if !found {
+			indent, found = leading, true
+			continue
+		}
+		for !strings.HasPrefix(leading, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+
+	decoded := make([]string, len(lines))
+	for i, l := range lines {
+		if strings.TrimSpace(l.text) == "" {
+			continue
+		}
+		decoded[i] = lexer.unescape(l.text[len(indent):], l.offset+len(indent))
+	}
+
+	return strings.Join(decoded, "\n")
 }
 
-func (lexer *Lexer) peekChar() byte {
-	if lexer.nextChar >= len(lexer.input) {
-		return 0
+var escapes = map[byte]string{
+	'n':  "\n",
+	't':  "\t",
+	'r':  "\r",
+	'0':  "\x00",
+	'"':  "\"",
+	'$':  "$",
+	'\'': "'",
+	'\\': "\\",
+}
+
+// Decodifica as sequências de escape de raw, que começa na posição offset do código fonte.
+// Além dos escapes de um char, aceita \uXXXX e \u{X...} com o código do caracter em hexadecimal
+func (lexer *Lexer) unescape(raw string, offset int) string {
+	var out strings.Builder
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			out.WriteByte(raw[i])
+			continue
+		}
+
+		if i+1 < len(raw) {
+			if escaped, ok := escapes[raw[i+1]]; ok {
+				out.WriteString(escaped)
+				i++
+				continue
+			}
+			if raw[i+1] == 'u' {
+				r, size, ok := decodeUnicodeEscape(raw[i+2:])
+				if !ok {
+					lexer.invalidEscape(offset+i, offset+i+2+size, "invalid unicode escape %s", raw[i:i+2+size])
+				} else {
+					out.WriteRune(r)
+				}
+				i += 1 + size
+				continue
+			}
+		}
+
+		if i+1 >= len(raw) {
+			lexer.invalidEscape(offset+i, offset+i+1, "escape sequence not terminated")
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(raw[i+1:])
+		lexer.invalidEscape(offset+i, offset+i+1+size, "unknown escape sequence %s", raw[i:i+1+size])
+		out.WriteString(raw[i+1 : i+1+size])
+		i += size
+	}
+
+	return out.String()
+}
+
+// Lê o código de um \u, retornando o caracter e quantos bytes de s foram usados
+func decodeUnicodeEscape(s string) (rune, int, bool) {
+	digits, size := "", 0
+
+	if strings.HasPrefix(s, "{") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return 0, len(s), false
+		}
+		digits, size = s[1:end], end+1
 	} else {
-		return lexer.input[lexer.nextChar]
+		for size < 4 && size < len(s) && isHexDigit(s[size]) {
+			size++
+		}
+		if size != 4 {
+			return 0, size, false
+		}
+		digits = s[:4]
 	}
 
+	if len(digits) == 0 || len(digits) > 6 {
+		return 0, size, false
+	}
+
+	code, err := strconv.ParseUint(digits, 16, 32)
+	r := rune(code)
+	if err != nil || !utf8.ValidRune(r) {
+		return 0, size, false
+	}
+
+	return r, size, true
 }
 
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+func isHexDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
 }
 
-func (lexer *Lexer) readIdentifier() string {
-	firstPosition := lexer.curChar
-	// a .. b
-	for isLetter(lexer.ch) {
-		lexer.readChar()
-	}
+func (lexer *Lexer) unterminated(start token.Position, what string, closing string) {
+	d := diagnostic.Errorf(start, lexer.position(), CodeUnterminatedString, "unterminated %s", what)
+	d.Suggestions = []string{"close the string with " + closing}
+	lexer.errors = append(lexer.errors, d)
+}
 
-	return lexer.input[firstPosition:lexer.curChar]
+func (lexer *Lexer) invalidEscape(from, to int, format string, a ...interface{}) {
+	d := diagnostic.Errorf(lexer.positionAt(from), lexer.positionAt(to), CodeInvalidEscape, format, a...)
+	d.Notes = []string{`valid escapes are \n \t \r \0 \" \' \\ \$ \uXXXX and \u{X...}`}
+	lexer.errors = append(lexer.errors, d)
 }
 
-func isDigit(ch byte) bool {
-	return '0' <= ch && ch <= '9' || ch == '.'
+// A posição de um offset qualquer do código fonte, usada nos erros de trechos que já foram lidos
+func (lexer *Lexer) positionAt(offset int) token.Position {
+	before := lexer.input[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(strings.TrimPrefix(before[strings.LastIndexByte(before, '\n')+1:], string(bom))) + 1
+
+	return token.Position{Filename: lexer.filename, Offset: offset, Line: line, Column: column}
 }
 
-func (lexer *Lexer) readNumber() string {
-	firstPosition := lexer.curChar
-	var number string
-	var midPosition int
-	var dot bool
+// Pula espaços em branco e comentários até o início do próximo token
+func (lexer *Lexer) skipTrivia() {
+	for {
+		switch {
+		case lexer.ch == ' ' || lexer.ch == '\t' || lexer.ch == '\n' || lexer.ch == '\r':
+			lexer.readChar()
+		case lexer.ch == '/' && lexer.peekChar() == '/':
+			lexer.readLineComment()
+		case lexer.ch == '/' && lexer.peekChar() == '*':
+			lexer.readBlockComment()
+		default:
+			return
+		}
+	}
+}
+
+// Ex: // comentário até o fim da linha
+func (lexer *Lexer) readLineComment() {
+	start := lexer.position()
+
+	for lexer.ch != '\n' && lexer.ch != 0 {
+		lexer.readChar()
+	}
 
-	for isDigit(lexer.ch) {
-		if lexer.ch == '.' {
-			number = lexer.input[firstPosition:lexer.curChar]
-			number = fmt.Sprintf("%s", number)
-			midPosition = lexer.curChar
-			dot = true
+	lexer.addComment(start)
+}
+
+// Ex: /* comentário */. Comentários de bloco podem ser aninhados: /* fora /* dentro */ ainda fora */
+func (lexer *Lexer) readBlockComment() {
+	start := lexer.position()
+	depth := 0
+
+	for {
+		switch {
+		case lexer.ch == 0:
+			d := diagnostic.Errorf(start, lexer.position(), CodeUnterminatedComment, "unterminated block comment")
+			d.Notes = []string{fmt.Sprintf("%d comment(s) still open at the end of the file", depth)}
+			d.Suggestions = []string{"close the comment with */"}
+			lexer.errors = append(lexer.errors, d)
+			lexer.addComment(start)
+			return
+		case lexer.ch == '/' && lexer.peekChar() == '*':
+			depth++
+			lexer.readChar()
+		case lexer.ch == '*' && lexer.peekChar() == '/':
+			depth--
+			lexer.readChar()
+			if depth == 0 {
+				lexer.readChar()
+				lexer.addComment(start)
+				return
+			}
 		}
 		lexer.readChar()
 	}
+}
 
-	floatin := fmt.Sprintf("%s%s", number, lexer.input[midPosition:lexer.curChar])
+func (lexer *Lexer) addComment(start token.Position) {
+	if !lexer.keepComments {
+		return
+	}
 
-	if dot {
-		return floatin
-	} else {
-		return lexer.input[firstPosition:lexer.curChar]
+	end := lexer.position()
+	comment := token.Comment{Text: lexer.input[start.Offset:end.Offset], Pos: start, End: end}
+	lexer.comments = append(lexer.comments, comment)
+}
+
+func newToken(tokenType token.Type, ch rune) token.Token {
+	return token.Token{Type: tokenType, Literal: string(ch)}
+}
+
+// O char n posições depois do próximo, ex: peekCharAt(0) é o mesmo que peekChar()
+func (lexer *Lexer) peekCharAt(n int) rune {
+	position := lexer.nextChar
+	for ; n > 0 && position < len(lexer.input); n-- {
+		_, size := utf8.DecodeRuneInString(lexer.input[position:])
+		position += size
 	}
 
+	if position >= len(lexer.input) {
+		return 0
+	}
+	ch, _ := utf8.DecodeRuneInString(lexer.input[position:])
+	return ch
+}
+
+func (lexer *Lexer) peekChar() rune {
+	return lexer.peekCharAt(0)
+}
+
+// Identificadores começam com uma letra de qualquer alfabeto ou _, ex: média, ação, _x
+func isLetter(ch rune) bool {
+	return ch == '_' || unicode.IsLetter(ch)
+}
+
+// Depois do primeiro caracter também valem dígitos e acentos combinados, como o de "é" escrito com dois caracteres
+func isIdentifierChar(ch rune) bool {
+	return isLetter(ch) || unicode.IsDigit(ch) || unicode.In(ch, unicode.Mn, unicode.Mc)
+}
+
+func (lexer *Lexer) readIdentifier() string {
+	firstPosition := lexer.curChar
+	// a .. b
+	for isIdentifierChar(lexer.ch) {
+		lexer.readChar()
+	}
+
+	return lexer.input[firstPosition:lexer.curChar]
 }
@@ -127,6 +127,59 @@ func TestLexer_NextToken(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "should tokenize logical operators",
+			input: "a && b || c",
+			want: []token.Token{
+				{Type: token.IDENT, Literal: "a"},
+				{Type: token.AND, Literal: "&&"},
+				{Type: token.IDENT, Literal: "b"},
+				{Type: token.OR, Literal: "||"},
+				{Type: token.IDENT, Literal: "c"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "should tokenize loop control keywords",
+			input: "break continue externo in",
+			want: []token.Token{
+				{Type: token.BREAK, Literal: "break"},
+				{Type: token.CONTINUE, Literal: "continue"},
+				{Type: token.IDENT, Literal: "externo"},
+				{Type: token.IN, Literal: "in"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "should tokenize compound assignment operators",
+			input: "+= -= *= /= ++ --",
+			want: []token.Token{
+				{Type: token.PLUS_ASSIGN, Literal: "+="},
+				{Type: token.MINUS_ASSIGN, Literal: "-="},
+				{Type: token.ASTERISK_ASSIGN, Literal: "*="},
+				{Type: token.SLASH_ASSIGN, Literal: "/="},
+				{Type: token.PLUSPLUS, Literal: "++"},
+				{Type: token.MINUSMINUS, Literal: "--"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "should tokenize arithmetic and bitwise operators",
+			input: "% ~/ & | |> ~ << >> <= >=",
+			want: []token.Token{
+				{Type: token.PERCENT, Literal: "%"},
+				{Type: token.FLOOR_DIV, Literal: "~/"},
+				{Type: token.AMPERSAND, Literal: "&"},
+				{Type: token.PIPE, Literal: "|"},
+				{Type: token.PIPELINE, Literal: "|>"},
+				{Type: token.TILDE, Literal: "~"},
+				{Type: token.SHL, Literal: "<<"},
+				{Type: token.SHR, Literal: ">>"},
+				{Type: token.LTE, Literal: "<="},
+				{Type: token.GTE, Literal: ">="},
+			},
+			wantErr: false,
+		},
 		{
 			name:  "inexistent token should be illegal",
 			input: ":=",
@@ -136,6 +189,50 @@ func TestLexer_NextToken(t *testing.T) {
 			wantErr: true,
 		},
 
+		{
+			name:  "should skip line comments",
+			input: "owo x :=: 10 // a nota\n// outra linha\nx / 2",
+			want: []token.Token{
+				{Type: token.OwO, Literal: "owo"},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.ASSIGN, Literal: ":=:"},
+				{Type: token.INT, Literal: "10"},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.SLASH, Literal: "/"},
+				{Type: token.INT, Literal: "2"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "should skip nested block comments",
+			input: "a /* fora /* dentro */ ainda fora */ /= b /**/ c",
+			want: []token.Token{
+				{Type: token.IDENT, Literal: "a"},
+				{Type: token.SLASH_ASSIGN, Literal: "/="},
+				{Type: token.IDENT, Literal: "b"},
+				{Type: token.IDENT, Literal: "c"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "should tokenize null and the null-aware operators",
+			input: "pessoa?.nome ?? lista?[0] ?? null ? x",
+			want: []token.Token{
+				{Type: token.IDENT, Literal: "pessoa"},
+				{Type: token.OPTIONAL_DOT, Literal: "?."},
+				{Type: token.IDENT, Literal: "nome"},
+				{Type: token.NULLISH, Literal: "??"},
+				{Type: token.IDENT, Literal: "lista"},
+				{Type: token.OPTIONAL_LBRACKET, Literal: "?["},
+				{Type: token.INT, Literal: "0"},
+				{Type: token.RBRACKET, Literal: "]"},
+				{Type: token.NULLISH, Literal: "??"},
+				{Type: token.NULL, Literal: "null"},
+				{Type: token.ILLEGAL, Literal: "?"},
+				{Type: token.IDENT, Literal: "x"},
+			},
+			wantErr: false,
+		},
 		{
 			name:  "should tokenize dot and comma",
 			input: ". ,",
@@ -151,22 +248,370 @@ func TestLexer_NextToken(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			l := New(tc.input)
 
-			var tokenEOF token.Token
-			tokenEOF.Type = token.EOF
-			tokenEOF.Literal = ""
-
 			tokenList := []token.Token{}
 
 			for {
 				tok := l.NextToken()
 
-				if tok == tokenEOF {
+				if tok.Type == token.EOF {
 					break
 				}
-				tokenList = append(tokenList, tok)
+				// As posições são verificadas em TestLexer_Positions
+				tokenList = append(tokenList, token.Token{Type: tok.Type, Literal: tok.Literal})
 			}
 
 			assert.Equal(t, tc.want, tokenList, "The tokenList must be equal!")
 		})
 	}
 }
+
+func TestLexer_Positions(t *testing.T) {
+	l := NewFile("notas.zooey", "owo nota :=: 10;\n\tshow(nota)")
+
+	type position struct {
+		literal   string
+		line      int
+		column    int
+		offset    int
+		endColumn int
+	}
+
+	want := []position{
+		{literal: "owo", line: 1, column: 1, offset: 0, endColumn: 4},
+		{literal: "nota", line: 1, column: 5, offset: 4, endColumn: 9},
+		{literal: ":=:", line: 1, column: 10, offset: 9, endColumn: 13},
+		{literal: "10", line: 1, column: 14, offset: 13, endColumn: 16},
+		{literal: ";", line: 1, column: 16, offset: 15, endColumn: 17},
+		{literal: "show", line: 2, column: 2, offset: 18, endColumn: 6},
+		{literal: "(", line: 2, column: 6, offset: 22, endColumn: 7},
+		{literal: "nota", line: 2, column: 7, offset: 23, endColumn: 11},
+		{literal: ")", line: 2, column: 11, offset: 27, endColumn: 12},
+		{literal: "", line: 2, column: 12, offset: 28, endColumn: 12},
+	}
+
+	for _, w := range want {
+		tok := l.NextToken()
+
+		assert.Equal(t, w.literal, tok.Literal)
+		assert.Equal(t, token.Position{Filename: "notas.zooey", Offset: w.offset, Line: w.line, Column: w.column}, tok.Pos, "start of %q", w.literal)
+		assert.Equal(t, w.line, tok.End.Line, "end line of %q", w.literal)
+		assert.Equal(t, w.endColumn, tok.End.Column, "end column of %q", w.literal)
+	}
+
+	assert.Equal(t, "notas.zooey:2:7", token.Position{Filename: "notas.zooey", Line: 2, Column: 7}.String())
+}
+
+func TestLexer_Comments(t *testing.T) {
+	t.Run("are kept as trivia when asked", func(t *testing.T) {
+		l := New("// media das notas\nowo media /* ainda sem notas */ :=: 0 // fim")
+		l.KeepComments()
+
+		owo := l.NextToken()
+		assert.Equal(t, []token.Comment{{
+			Text: "// media das notas",
+			Pos:  token.Position{Offset: 0, Line: 1, Column: 1},
+			End:  token.Position{Offset: 18, Line: 1, Column: 19},
+		}}, owo.Comments)
+
+		l.NextToken()
+		assign := l.NextToken()
+		assert.Len(t, assign.Comments, 1)
+		assert.Equal(t, "/* ainda sem notas */", assign.Comments[0].Text)
+
+		l.NextToken()
+		eof := l.NextToken()
+		assert.Equal(t, token.Type(token.EOF), eof.Type)
+		assert.Equal(t, "// fim", eof.Comments[0].Text)
+	})
+
+	t.Run("are dropped by default", func(t *testing.T) {
+		l := New("// nada\nx")
+
+		assert.Nil(t, l.NextToken().Comments)
+	})
+
+	t.Run("unterminated block comment is an error", func(t *testing.T) {
+		source := "owo x :=: 1\n/* fora /* dentro */\nx"
+		l := New(source)
+
+		for l.NextToken().Type != token.EOF {
+		}
+
+		assert.Len(t, l.Errors(), 1)
+		assert.Equal(t, "2:1: error[L001]: unterminated block comment\n/* fora /* dentro */\n^^^^^^^^^^^^^^^^^^^^\n  = note: 1 comment(s) still open at the end of the file\n  = help: close the comment with */", l.Errors()[0].Render(source))
+	})
+}
+
+func TestLexer_Strings(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr []string
+	}{
+		{name: "plain string", input: `"Zooey"`, want: "Zooey"},
+		{name: "single char escapes", input: `"a\tb\nc\\d\"e\'f\0"`, want: "a\tb\nc\\d\"e'f\x00"},
+		{name: "unicode escapes", input: `"caf\u00e9 \u{1F408}"`, want: "café 🐈"},
+		{name: "raw string keeps backslashes", input: "`C:\\zooey\\n`", want: `C:\zooey\n`},
+		{name: "raw string spans lines", input: "`a\n  b`", want: "a\n  b"},
+		{
+			name:  "multi-line string strips common indentation",
+			input: "\"\"\"\n\t\tSELECT nome\n\t\t  FROM alunos\n\n\t\tWHERE nota > 7\\t\n\t\t\"\"\"",
+			want:  "SELECT nome\n  FROM alunos\n\nWHERE nota > 7\t",
+		},
+		{name: "multi-line string on one line", input: `"""diz "oi" """`, want: `diz "oi" `},
+		{
+			name:    "unknown escape",
+			input:   `"nota\q"`,
+			want:    "notaq",
+			wantErr: []string{`1:6: error[L003]: unknown escape sequence \q`},
+		},
+		{
+			name:    "invalid unicode escape",
+			input:   `"\u12G4 \u{110000}"`,
+			want:    "G4 ",
+			wantErr: []string{`1:2: error[L003]: invalid unicode escape \u12`, `1:9: error[L003]: invalid unicode escape \u{110000}`},
+		},
+		{
+			name:    "string ends at the end of the line",
+			input:   "\"sem fim\nowo",
+			want:    "sem fim",
+			wantErr: []string{"1:1: error[L002]: unterminated string literal"},
+		},
+		{
+			name:    "raw string ends at the end of the file",
+			input:   "`sem fim",
+			want:    "sem fim",
+			wantErr: []string{"1:1: error[L002]: unterminated raw string literal"},
+		},
+		{
+			name:    "multi-line string ends at the end of the file",
+			input:   "x \"\"\"\n  sem fim\n",
+			want:    "sem fim",
+			wantErr: []string{"1:3: error[L002]: unterminated multi-line string literal"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l := New(tc.input)
+
+			tok := l.NextToken()
+			if tok.Type != token.STRING {
+				tok = l.NextToken()
+			}
+
+			assert.Equal(t, token.Type(token.STRING), tok.Type)
+			assert.Equal(t, tc.want, tok.Literal)
+
+			errors := []string{}
+			for _, d := range l.Errors() {
+				errors = append(errors, d.String())
+			}
+			if tc.wantErr == nil {
+				tc.wantErr = []string{}
+			}
+			assert.Equal(t, tc.wantErr, errors)
+		})
+	}
+}
+
+func TestLexer_TemplateStrings(t *testing.T) {
+	l := New(`"média: ${notas[0]} e ${ {"a": 1}["a"] }!" "fim"`)
+
+	want := []token.Token{
+		{Type: token.TEMPLATE_HEAD, Literal: "média: "},
+		{Type: token.IDENT, Literal: "notas"},
+		{Type: token.LBRACKET, Literal: "["},
+		{Type: token.INT, Literal: "0"},
+		{Type: token.RBRACKET, Literal: "]"},
+		{Type: token.TEMPLATE_MIDDLE, Literal: " e "},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.STRING, Literal: "a"},
+		{Type: token.COLON, Literal: ":"},
+		{Type: token.INT, Literal: "1"},
+		{Type: token.RBRACE, Literal: "}"},
+		{Type: token.LBRACKET, Literal: "["},
+		{Type: token.STRING, Literal: "a"},
+		{Type: token.RBRACKET, Literal: "]"},
+		{Type: token.TEMPLATE_TAIL, Literal: "!"},
+		{Type: token.STRING, Literal: "fim"},
+	}
+
+	for _, w := range want {
+		tok := l.NextToken()
+		assert.Equal(t, w, token.Token{Type: tok.Type, Literal: tok.Literal})
+	}
+	assert.Empty(t, l.Errors())
+}
+
+func TestLexer_UnicodeSource(t *testing.T) {
+	t.Run("identifiers follow unicode letters and digits", func(t *testing.T) {
+		l := New("owo média :=: nota1 + ação_2 + cafe\u0301 + 日本")
+
+		want := []token.Token{
+			{Type: token.OwO, Literal: "owo"},
+			{Type: token.IDENT, Literal: "média"},
+			{Type: token.ASSIGN, Literal: ":=:"},
+			{Type: token.IDENT, Literal: "nota1"},
+			{Type: token.PLUS, Literal: "+"},
+			{Type: token.IDENT, Literal: "ação_2"},
+			{Type: token.PLUS, Literal: "+"},
+			// "e" seguido do acento combinado U+0301
+			{Type: token.IDENT, Literal: "cafe\u0301"},
+			{Type: token.PLUS, Literal: "+"},
+			{Type: token.IDENT, Literal: "日本"},
+			{Type: token.EOF, Literal: ""},
+		}
+
+		for _, w := range want {
+			tok := l.NextToken()
+			assert.Equal(t, w, token.Token{Type: tok.Type, Literal: tok.Literal})
+		}
+	})
+
+	t.Run("columns are counted in characters", func(t *testing.T) {
+		l := New("\"ação\" + média")
+
+		str := l.NextToken()
+		assert.Equal(t, 1, str.Pos.Column)
+		assert.Equal(t, 7, str.End.Column)
+
+		plus := l.NextToken()
+		assert.Equal(t, 8, plus.Pos.Column)
+		assert.Equal(t, 9, plus.Pos.Offset)
+
+		ident := l.NextToken()
+		assert.Equal(t, 10, ident.Pos.Column)
+		assert.Equal(t, 15, ident.End.Column)
+	})
+
+	t.Run("a leading BOM is skipped", func(t *testing.T) {
+		l := New("\uFEFFowo x")
+
+		tok := l.NextToken()
+		assert.Equal(t, token.Token{Type: token.OwO, Literal: "owo"}, token.Token{Type: tok.Type, Literal: tok.Literal})
+		assert.Equal(t, token.Position{Offset: 3, Line: 1, Column: 1}, tok.Pos)
+	})
+
+	t.Run("characters outside the language are a single illegal token", func(t *testing.T) {
+		l := New("🐈 \xff x")
+
+		want := []token.Token{
+			{Type: token.ILLEGAL, Literal: "🐈"},
+			{Type: token.ILLEGAL, Literal: "\xff"},
+			{Type: token.IDENT, Literal: "x"},
+		}
+
+		for _, w := range want {
+			tok := l.NextToken()
+			assert.Equal(t, w, token.Token{Type: tok.Type, Literal: tok.Literal})
+		}
+	})
+
+	t.Run("escape errors point at the right character", func(t *testing.T) {
+		source := "\uFEFF\"ação \\q\""
+		l := New(source)
+		l.NextToken()
+
+		d := l.Errors()[0]
+		d.Notes = nil
+		assert.Equal(t, "1:7: error[L003]: unknown escape sequence \\q\n\"ação \\q\"\n      ^^", d.Render(source))
+	})
+}
+
+func TestLexer_Numbers(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []token.Token
+		wantErr string
+	}{
+		{input: "42", want: []token.Token{{Type: token.INT, Literal: "42"}}},
+		{input: "1_000_000", want: []token.Token{{Type: token.INT, Literal: "1_000_000"}}},
+		{input: "0xFF 0X_1f", want: []token.Token{{Type: token.INT, Literal: "0xFF"}, {Type: token.INT, Literal: "0X_1f"}}},
+		{input: "0b1010 0o17", want: []token.Token{{Type: token.INT, Literal: "0b1010"}, {Type: token.INT, Literal: "0o17"}}},
+		{input: "3.14 0.5 1e9 2.5E-3 1_0.0_1e+1_0", want: []token.Token{
+			{Type: token.FLOAT, Literal: "3.14"},
+			{Type: token.FLOAT, Literal: "0.5"},
+			{Type: token.FLOAT, Literal: "1e9"},
+			{Type: token.FLOAT, Literal: "2.5E-3"},
+			{Type: token.FLOAT, Literal: "1_0.0_1e+1_0"},
+		}},
+		{input: "12.50d 3d 1.5e3d 0d", want: []token.Token{
+			{Type: token.DECIMAL, Literal: "12.50d"},
+			{Type: token.DECIMAL, Literal: "3d"},
+			{Type: token.DECIMAL, Literal: "1.5e3d"},
+			{Type: token.DECIMAL, Literal: "0d"},
+		}},
+		{input: "1..5 0.5..1.5", want: []token.Token{
+			{Type: token.INT, Literal: "1"},
+			{Type: token.DOTDOT, Literal: ".."},
+			{Type: token.INT, Literal: "5"},
+			{Type: token.FLOAT, Literal: "0.5"},
+			{Type: token.DOTDOT, Literal: ".."},
+			{Type: token.FLOAT, Literal: "1.5"},
+		}},
+		{input: "[a, b = 0]", want: []token.Token{
+			{Type: token.LBRACKET, Literal: "["},
+			{Type: token.IDENT, Literal: "a"},
+			{Type: token.COMMA, Literal: ","},
+			{Type: token.IDENT, Literal: "b"},
+			{Type: token.DEFAULT, Literal: "="},
+			{Type: token.INT, Literal: "0"},
+			{Type: token.RBRACKET, Literal: "]"},
+		}},
+		{input: "[1, ...resto] => x", want: []token.Token{
+			{Type: token.LBRACKET, Literal: "["},
+			{Type: token.INT, Literal: "1"},
+			{Type: token.COMMA, Literal: ","},
+			{Type: token.ELLIPSIS, Literal: "..."},
+			{Type: token.IDENT, Literal: "resto"},
+			{Type: token.RBRACKET, Literal: "]"},
+			{Type: token.ARROW, Literal: "=>"},
+			{Type: token.IDENT, Literal: "x"},
+		}},
+		{input: "tupla.0 nota.media", want: []token.Token{
+			{Type: token.IDENT, Literal: "tupla"},
+			{Type: token.DOT, Literal: "."},
+			{Type: token.INT, Literal: "0"},
+			{Type: token.IDENT, Literal: "nota"},
+			{Type: token.DOT, Literal: "."},
+			{Type: token.IDENT, Literal: "media"},
+		}},
+		{input: "1.5dd", want: []token.Token{{Type: token.ILLEGAL, Literal: "1.5dd"}}, wantErr: "1:4: error[L004]: invalid digit 'd' in decimal literal"},
+		{input: "1.2.3", want: []token.Token{{Type: token.ILLEGAL, Literal: "1.2.3"}}, wantErr: "1:1: error[L004]: malformed number 1.2.3: more than one decimal point"},
+		{input: "0b102", want: []token.Token{{Type: token.ILLEGAL, Literal: "0b102"}}, wantErr: "1:5: error[L004]: invalid digit '2' in binary literal"},
+		{input: "0o8", want: []token.Token{{Type: token.ILLEGAL, Literal: "0o8"}}, wantErr: "1:3: error[L004]: invalid digit '8' in octal literal"},
+		{input: "12abc", want: []token.Token{{Type: token.ILLEGAL, Literal: "12abc"}}, wantErr: "1:3: error[L004]: invalid digit 'a' in decimal literal"},
+		{input: "0x", want: []token.Token{{Type: token.ILLEGAL, Literal: "0x"}}, wantErr: "1:1: error[L004]: hexadecimal literal 0x has no digits"},
+		{input: "1__000", want: []token.Token{{Type: token.ILLEGAL, Literal: "1__000"}}, wantErr: "1:2: error[L004]: '_' must separate successive digits"},
+		{input: "100_", want: []token.Token{{Type: token.ILLEGAL, Literal: "100_"}}, wantErr: "1:4: error[L004]: '_' must separate successive digits"},
+		{input: "1_.5", want: []token.Token{{Type: token.ILLEGAL, Literal: "1_.5"}}, wantErr: "1:2: error[L004]: '_' must separate successive digits"},
+		{input: "1e", want: []token.Token{{Type: token.ILLEGAL, Literal: "1e"}}, wantErr: "1:1: error[L004]: exponent of 1e has no digits"},
+		{input: "2e-", want: []token.Token{{Type: token.ILLEGAL, Literal: "2e-"}}, wantErr: "1:1: error[L004]: exponent of 2e- has no digits"},
+		{input: "017", want: []token.Token{{Type: token.ILLEGAL, Literal: "017"}}, wantErr: "1:1: error[L004]: decimal literal 017 cannot start with 0"},
+		{input: ".5", want: []token.Token{{Type: token.ILLEGAL, Literal: ".5"}}, wantErr: "1:1: error[L004]: float literal .5 has no digits before the dot"},
+		{input: "1. + 2", want: []token.Token{
+			{Type: token.ILLEGAL, Literal: "1."},
+			{Type: token.PLUS, Literal: "+"},
+			{Type: token.INT, Literal: "2"},
+		}, wantErr: "1:1: error[L004]: float literal 1. has no digits after the dot"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			l := New(tc.input)
+
+			for _, w := range tc.want {
+				tok := l.NextToken()
+				assert.Equal(t, w, token.Token{Type: tok.Type, Literal: tok.Literal})
+			}
+			assert.Equal(t, token.Type(token.EOF), l.NextToken().Type)
+
+			if tc.wantErr == "" {
+				assert.Empty(t, l.Errors())
+				return
+			}
+			assert.Len(t, l.Errors(), 1)
+			assert.Equal(t, tc.wantErr, l.Errors()[0].String())
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package Lexer
+
+import (
+	"fmt"
+	"strings"
+
+	diagnostic "github.com/ZooeyLang/Diagnostic"
+	token "github.com/ZooeyLang/Token"
+)
+
+var baseNames = map[int]string{
+	2:  "binary",
+	8:  "octal",
+	10: "decimal",
+	16: "hexadecimal",
+}
+
+// Ex: 42, 1_000_000, 0xFF, 0b1010, 0o17, 3.14, 1e9, 2.5E-3 e 12.50d, que é um Decimal
+//
+// O scanner lê tudo que pode fazer parte de um número, incluindo letras, e só depois valida o literal.
+// Assim 0b102 vira um único erro apontando para o 2, em vez de um número seguido de um identificador.
+// O ponto só faz parte do número quando vem seguido de um dígito, então 1..5 continua sendo lido
+// como tokens separados
+func (lexer *Lexer) readNumber() token.Token {
+	first := lexer.curChar
+	start := lexer.position()
+
+	base := 10
+	if lexer.ch == '0' {
+		switch lexer.peekChar() {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+		if base != 10 {
+			lexer.readChar()
+			lexer.readChar()
+		}
+	}
+
+	float := false
+	for {
+		if base == 10 && (lexer.ch == 'e' || lexer.ch == 'E') {
+			float = true
+			if next := lexer.peekChar(); next == '+' || next == '-' {
+				lexer.readChar()
+			}
+		} else if base == 10 && lexer.ch == '.' && isDecimalDigit(lexer.peekChar()) {
+			float = true
+		} else if !isIdentifierChar(lexer.ch) {
+			break
+		}
+		lexer.readChar()
+	}
+
+	literal := lexer.input[first:lexer.curChar]
+	tok := token.Token{Type: token.INT, Literal: literal}
+	if float {
+		tok.Type = token.FLOAT
+	}
+
+	// O sufixo d marca um Decimal, que pode ou não ter ponto: 12d, 12.50d, 1.5e3d
+	digits := literal
+	if base == 10 && strings.HasSuffix(literal, "d") {
+		digits = literal[:len(literal)-1]
+		tok.Type = token.DECIMAL
+	}
+
+	if message, at := checkNumber(digits, base, float); message != "" {
+		lexer.malformedNumber(start, first, at, message)
+		tok.Type = token.ILLEGAL
+		return tok
+	}
+
+	// 1. não é um float: o ponto precisa de um dígito depois dele
+	if lexer.ch == '.' && !isLetter(lexer.peekChar()) && lexer.peekChar() != '.' {
+		lexer.readChar()
+		d := diagnostic.Errorf(start, lexer.position(), CodeMalformedNumber, "float literal %s has no digits after the dot", lexer.input[first:lexer.curChar])
+		d.Suggestions = []string{fmt.Sprintf("write %s.0", literal)}
+		lexer.errors = append(lexer.errors, d)
+		tok.Type = token.ILLEGAL
+		tok.Literal = lexer.input[first:lexer.curChar]
+	}
+
+	return tok
+}
+
+// Ex: .5, que precisa ser escrito como 0.5
+func (lexer *Lexer) readLeadingDotNumber() token.Token {
+	first := lexer.curChar
+	start := lexer.position()
+
+	lexer.readChar()
+	for isIdentifierChar(lexer.ch) {
+		lexer.readChar()
+	}
+
+	literal := lexer.input[first:lexer.curChar]
+	d := diagnostic.Errorf(start, lexer.position(), CodeMalformedNumber, "float literal %s has no digits before the dot", literal)
+	d.Suggestions = []string{fmt.Sprintf("write 0%s", literal)}
+	lexer.errors = append(lexer.errors, d)
+
+	return token.Token{Type: token.ILLEGAL, Literal: literal}
+}
+
+// Valida um literal lido por readNumber. Retorna a mensagem de erro e o índice do caracter problemático
+// dentro do literal, ou -1 quando o problema é o literal inteiro
+func checkNumber(literal string, base int, float bool) (string, int) {
+	digits, offset := literal, 0
+	if base != 10 {
+		digits, offset = literal[2:], 2
+	}
+	if digits == "" {
+		return fmt.Sprintf("%s literal %s has no digits", baseNames[base], literal), -1
+	}
+
+	mantissa, exponent := digits, ""
+	if base == 10 {
+		if i := strings.IndexAny(digits, "eE"); i >= 0 {
+			mantissa, exponent = digits[:i], digits[i+1:]
+		}
+	}
+
+	if i := strings.Index(mantissa, "."); i >= 0 && strings.Contains(mantissa[i+1:], ".") {
+		return fmt.Sprintf("malformed number %s: more than one decimal point", literal), -1
+	}
+
+	// Cada trecho entre os pontos e o expoente é validado separado, já que um _ não pode encostar neles
+	position := offset
+	for i, part := range strings.Split(mantissa, ".") {
+		if message, at := checkDigits(part, base, i == 0 && base != 10); message != "" {
+			return message, position + at
+		}
+		position += len(part) + 1
+	}
+
+	if exponent != "" || strings.ContainsAny(digits, "eE") {
+		position = offset + len(mantissa) + 1
+		if strings.HasPrefix(exponent, "+") || strings.HasPrefix(exponent, "-") {
+			exponent = exponent[1:]
+			position++
+		}
+		if exponent == "" {
+			return fmt.Sprintf("exponent of %s has no digits", literal), -1
+		}
+		if message, at := checkDigits(exponent, 10, false); message != "" {
+			return message, position + at
+		}
+	}
+
+	if base == 10 && !float && len(mantissa) > 1 && mantissa[0] == '0' {
+		return fmt.Sprintf("decimal literal %s cannot start with 0", literal), -1
+	}
+
+	return "", -1
+}
+
+// Confere se todos os caracteres de s são dígitos da base e se cada _ está entre dois dígitos.
+// afterPrefix permite um _ logo depois do prefixo, ex: 0x_FF
+func checkDigits(s string, base int, afterPrefix bool) (string, int) {
+	for i, ch := range s {
+		if ch == '_' {
+			before := i > 0 && s[i-1] != '_' || i == 0 && afterPrefix
+			after := i+1 < len(s) && s[i+1] != '_'
+			if !before || !after {
+				return "'_' must separate successive digits", i
+			}
+			continue
+		}
+		if digitValue(ch) >= base {
+			return fmt.Sprintf("invalid digit %q in %s literal", ch, baseNames[base]), i
+		}
+	}
+	return "", -1
+}
+
+// O valor de um dígito em qualquer base até 16; caracteres que não são dígitos valem 16
+func digitValue(ch rune) int {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return int(ch - '0')
+	case 'a' <= ch && ch <= 'f':
+		return int(ch-'a') + 10
+	case 'A' <= ch && ch <= 'F':
+		return int(ch-'A') + 10
+	}
+	return 16
+}
+
+// Tokens depois dos quais um . é um acesso a campo, e não o começo de um número
+func endsOperand(t token.Type) bool {
+	switch t {
+	case token.IDENT, token.INT, token.FLOAT, token.DECIMAL, token.STRING, token.TEMPLATE_TAIL,
+		token.RPAREN, token.RBRACKET, token.RBRACE:
+		return true
+	}
+	return false
+}
+
+func isDecimalDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func (lexer *Lexer) malformedNumber(start token.Position, first int, at int, message string) {
+	end := lexer.position()
+	if at >= 0 {
+		start = lexer.positionAt(first + at)
+		end = lexer.positionAt(first + at + 1)
+	}
+
+	lexer.errors = append(lexer.errors, diagnostic.Errorf(start, end, CodeMalformedNumber, "%s", message))
+}
@@ -8,6 +8,26 @@ func NewEnvironment() *Environment {
 type Environment struct {
 	store map[string]Object
 	outer *Environment // Extendendo o environment
+
+	roundingMode string // Modo de arredondamento de Decimals do programa, guardado no environment global
+}
+
+// O modo de arredondamento do programa, vazio enquanto for o padrão
+func (e *Environment) RoundingMode() string {
+	return e.root().roundingMode
+}
+
+func (e *Environment) SetRoundingMode(mode string) {
+	e.root().roundingMode = mode
+}
+
+// O environment global do programa, do qual todos os outros descendem
+func (e *Environment) root() *Environment {
+	env := e
+	for env.outer != nil {
+		env = env.outer
+	}
+	return env
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
@@ -23,6 +43,59 @@ func (e *Environment) Set(name string, val Object) Object {
 	return val
 }
 
+// Atualiza a variável no escopo em que ela foi declarada, retornando false se ela não existir
+func (e *Environment) Assign(name string, val Object) bool {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return false
+}
+
+// O escopo que está depth níveis acima deste
+func (e *Environment) ancestor(depth int) *Environment {
+	env := e
+	for i := 0; i < depth && env != nil; i++ {
+		env = env.outer
+	}
+	return env
+}
+
+// Busca a variável diretamente no escopo em que o resolver a encontrou
+func (e *Environment) GetAt(depth int, name string) (Object, bool) {
+	env := e.ancestor(depth)
+	if env == nil {
+		return nil, false
+	}
+	obj, ok := env.store[name]
+	return obj, ok
+}
+
+// Atualiza a variável diretamente no escopo em que o resolver a encontrou
+func (e *Environment) AssignAt(depth int, name string, val Object) bool {
+	env := e.ancestor(depth)
+	if env == nil {
+		return false
+	}
+	if _, ok := env.store[name]; !ok {
+		return false
+	}
+	env.store[name] = val
+	return true
+}
+
+// Uma cópia rasa deste escopo, ligada ao mesmo escopo externo
+func (e *Environment) Copy() *Environment {
+	env := NewEnclosedEnvironment(e.outer)
+	for name, val := range e.store {
+		env.store[name] = val
+	}
+	return env
+}
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
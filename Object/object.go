@@ -2,17 +2,21 @@ package object
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
+	"math/big"
 	"strings"
 
 	ast "github.com/ZooeyLang/AST"
+	token "github.com/ZooeyLang/Token"
 )
 
 type ObjectType string
 
 const (
 	INTEGER_OBJ      = "INTEGER"
+	BIGINT_OBJ       = "BIGINT"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NULL_OBJ         = "NULL"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
@@ -21,9 +25,12 @@ const (
 	BUILTIN_OBJ      = "BUILTIN"
 	ARRAY_OBJ        = "ARRAY"
 	FLOAT            = "FLOAT"
+	DECIMAL_OBJ      = "DECIMAL"
 	HASH_OBJ         = "HASH"
+	TUPLE_OBJ        = "TUPLE"
 	ERROR_OBJ        = "ERROR"
-	OWO_OBJ          = "OWO"
+	BREAK_OBJ        = "BREAK"
+	CONTINUE_OBJ     = "CONTINUE"
 )
 
 type Object interface {
@@ -38,6 +45,15 @@ type Integer struct {
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 
+// Um inteiro que não cabe em int64. O evaluator só cria BigInts fora desse intervalo,
+// então um mesmo valor nunca aparece como Integer e BigInt ao mesmo tempo
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Inspect() string  { return b.Value.String() }
+func (b *BigInt) Type() ObjectType { return BIGINT_OBJ }
+
 type Boolean struct {
 	Value bool
 }
@@ -57,26 +73,41 @@ type ReturnValue struct {
 func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
+// Break e Continue são sinais de controle de fluxo que sobem pelos blocos até o laço de mesmo Label
+type Break struct {
+	Label string
+}
+
+func (b *Break) Type() ObjectType { return BREAK_OBJ }
+func (b *Break) Inspect() string  { return "break" }
+
+type Continue struct {
+	Label string
+}
+
+func (c *Continue) Type() ObjectType { return CONTINUE_OBJ }
+func (c *Continue) Inspect() string  { return "continue" }
+
 type Error struct {
 	Message string
+	Pos     token.Position // Onde, no código, o erro aconteceu
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
-type OwO struct {
-	Expressions []*ast.Expression
-	Env         *Environment
-}
-
-func (f *OwO) Type() ObjectType { return OWO_OBJ }
-func (f *OwO) Inspect() string {
-	return "hello world"
+// Descreve o erro com a sua posição e o trecho do código em que ele aconteceu
+func (e *Error) Report(source string) string {
+	report := e.Pos.String() + ": " + e.Inspect()
+	if excerpt := token.Excerpt(source, e.Pos); excerpt != "" {
+		report += "\n" + excerpt
+	}
+	return report
 }
 
 type Function struct {
 	FnName     string
-	Parameters []*ast.Identifier
+	Parameters []ast.Pattern
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -109,7 +140,8 @@ func (s *String) Inspect() string {
 	return s.Value
 }
 
-type BuiltinFunction func(args ...Object) Object
+// env é o environment de quem chama a função
+type BuiltinFunction func(env *Environment, args ...Object) Object
 
 type Builtin struct {
 	Fn BuiltinFunction
@@ -135,6 +167,20 @@ func (ao *Array) Inspect() string {
 	return out.String()
 }
 
+// Uma sequência imutável, como a devolvida por return a, b
+type Tuple struct {
+	Elements []Object
+}
+
+func (t *Tuple) Type() ObjectType { return TUPLE_OBJ }
+func (t *Tuple) Inspect() string {
+	elements := []string{}
+	for _, e := range t.Elements {
+		elements = append(elements, e.Inspect())
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
 type Float struct {
 	Value float64
 }
@@ -142,6 +188,30 @@ type Float struct {
 func (b *Float) Type() ObjectType { return FLOAT }
 func (b *Float) Inspect() string  { return fmt.Sprintf("%f", b.Value) }
 
+// Um número exato em base 10, valendo Unscaled × 10^-Scale. A escala nunca é negativa e guarda
+// os zeros à direita, então 12.50d é {1250, 2} e aparece como 12.50
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int
+}
+
+func (d *Decimal) Type() ObjectType { return DECIMAL_OBJ }
+func (d *Decimal) Inspect() string {
+	digits := new(big.Int).Abs(d.Unscaled).String()
+
+	if d.Scale > 0 {
+		if len(digits) <= d.Scale {
+			digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.Scale] + "." + digits[len(digits)-d.Scale:]
+	}
+
+	if d.Unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -163,6 +233,39 @@ func (i *Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+// 1.0d e 1.00d são iguais, então os zeros à direita são descartados antes do hash.
+// Um Decimal inteiro usa a chave do Integer ou BigInt de mesmo valor, já que 1.0d == 1
+func (d *Decimal) HashKey() HashKey {
+	unscaled, scale := new(big.Int).Set(d.Unscaled), d.Scale
+	ten, remainder := big.NewInt(10), new(big.Int)
+	for scale > 0 {
+		quotient, _ := new(big.Int).QuoRem(unscaled, ten, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		unscaled, scale = quotient, scale-1
+	}
+
+	if scale == 0 {
+		if unscaled.IsInt64() {
+			return (&Integer{Value: unscaled.Int64()}).HashKey()
+		}
+		return (&BigInt{Value: unscaled}).HashKey()
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("%se%d", unscaled, scale)))
+
+	return HashKey{Type: d.Type(), Value: h.Sum64()}
+}
+
+func (b *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(b.Value.String()))
+
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
 func (s *String) HashKey() HashKey {
 	h := fnv.New64a()
 	h.Write([]byte(s.Value))
@@ -170,6 +273,27 @@ func (s *String) HashKey() HashKey {
 	return HashKey{Type: s.Type(), Value: h.Sum64()}
 }
 
+// O hash de cada elemento entra no da tupla junto com o seu tipo, então (1, "1") e ("1", 1) diferem.
+// Uma tupla só pode ser chave de um hash quando todos os seus elementos também podem; se algum não puder,
+// como um array, o resultado é a chave vazia, HashKey{}, que nenhum outro valor usa
+func (t *Tuple) HashKey() HashKey {
+	h := fnv.New64a()
+	for _, e := range t.Elements {
+		hashable, ok := e.(Hashable)
+		if !ok {
+			return HashKey{}
+		}
+		key := hashable.HashKey()
+		if key == (HashKey{}) {
+			return HashKey{}
+		}
+		h.Write([]byte(key.Type))
+		binary.Write(h, binary.LittleEndian, key.Value)
+	}
+
+	return HashKey{Type: t.Type(), Value: h.Sum64()}
+}
+
 type HashPair struct {
 	Key   Object
 	Value Object
@@ -1,11 +1,15 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
-
+	"math"
+	"math/big"
 	"strconv"
+	"strings"
 
 	ast "github.com/ZooeyLang/AST"
+	diagnostic "github.com/ZooeyLang/Diagnostic"
 	"github.com/ZooeyLang/Lexer"
 	token "github.com/ZooeyLang/Token"
 )
@@ -13,31 +17,73 @@ import (
 const (
 	_int = iota
 	LOWEST
+	ASSIGN
+	NULLISH
+	LOGICAL_OR
+	LOGICAL_AND
 	EQUALS
 	LESSGREATER
+	PIPELINE
+	BIT_OR
+	BIT_XOR
+	BIT_AND
+	SHIFT
 	SUM
 	PRODUCT
 	POTENTIATION
 	PREFIX
 	CALL
 	INDEX
+	POSTFIX
+)
+
+// Códigos dos diagnósticos gerados pelo parser
+const (
+	CodeUnexpectedToken    = "P001"
+	CodeExpectedExpression = "P002"
+	CodeInvalidAssignment  = "P003"
+	CodeInvalidLabel       = "P004"
+	CodeInvalidNumber      = "P005"
+	CodeIllegalCharacter   = "P006"
+	CodeInvalidPattern     = "P007"
+	CodeInvalidArgument    = "P008"
 )
 
 var precedences = map[token.Type]int{
-	token.EQ:         EQUALS,
-	token.NOT_EQ:     EQUALS,
-	token.LT:         LESSGREATER,
-	token.GT:         LESSGREATER,
-	token.GTE:        LESSGREATER,
-	token.LTE:        LESSGREATER,
-	token.PLUS:       SUM,
-	token.MINUSMINUS: SUM,
-	token.MINUS:      SUM,
-	token.SLASH:      PRODUCT,
-	token.ASTERISK:   PRODUCT,
-	token.POW:        POTENTIATION,
-	token.LBRACKET:   INDEX,
-	token.LPAREN:     CALL,
+	token.ASSIGN:            ASSIGN,
+	token.PLUS_ASSIGN:       ASSIGN,
+	token.MINUS_ASSIGN:      ASSIGN,
+	token.ASTERISK_ASSIGN:   ASSIGN,
+	token.SLASH_ASSIGN:      ASSIGN,
+	token.NULLISH:           NULLISH,
+	token.OR:                LOGICAL_OR,
+	token.AND:               LOGICAL_AND,
+	token.EQ:                EQUALS,
+	token.NOT_EQ:            EQUALS,
+	token.LT:                LESSGREATER,
+	token.GT:                LESSGREATER,
+	token.GTE:               LESSGREATER,
+	token.LTE:               LESSGREATER,
+	token.PIPELINE:          PIPELINE,
+	token.PIPE:              BIT_OR,
+	token.TILDE:             BIT_XOR,
+	token.AMPERSAND:         BIT_AND,
+	token.SHL:               SHIFT,
+	token.SHR:               SHIFT,
+	token.PLUS:              SUM,
+	token.MINUS:             SUM,
+	token.SLASH:             PRODUCT,
+	token.ASTERISK:          PRODUCT,
+	token.PERCENT:           PRODUCT,
+	token.FLOOR_DIV:         PRODUCT,
+	token.POW:               POTENTIATION,
+	token.LBRACKET:          INDEX,
+	token.DOT:               INDEX,
+	token.OPTIONAL_DOT:      INDEX,
+	token.OPTIONAL_LBRACKET: INDEX,
+	token.LPAREN:            CALL,
+	token.PLUSPLUS:          POSTFIX,
+	token.MINUSMINUS:        POSTFIX,
 }
 
 type Parser struct {
@@ -46,7 +92,22 @@ type Parser struct {
 	currentToken token.Token
 	peekToken    token.Token
 
-	errors []string
+	errors []diagnostic.Diagnostic
+
+	// Depois de um erro o parser fica em "pânico" até chegar no início da próxima instrução.
+	// Enquanto isso os erros seguintes são ignorados, já que quase sempre são consequência do primeiro
+	panicking bool
+
+	// Quantos { estão abertos no token atual, e em quais dessas profundidades começam os blocos
+	// sendo parseados. A recuperação só para no } que fecha um bloco, e não no de um hash ou match
+	braces int
+	blocks []int
+
+	// Quantos erros do lexer já foram copiados para errors
+	lexerErrors int
+
+	// Labels dos laços que estão sendo parseados, do mais externo ao mais interno
+	labels []string
 
 	prefixParseFns map[token.Type]prefixParseFn
 	infixParseFns  map[token.Type]infixParseFn
@@ -58,25 +119,30 @@ type (
 )
 
 func New(lexer *Lexer.Lexer) *Parser {
-	p := &Parser{l: lexer, errors: []string{}}
+	p := &Parser{l: lexer, errors: []diagnostic.Diagnostic{}}
 
 	// Assign an specific func based on the token it represents
 	p.prefixParseFns = make(map[token.Type]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
 	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(token.DECIMAL, p.parseDecimalLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.TILDE, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNull)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.FN, p.parseFunctionLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.TEMPLATE_HEAD, p.parseTemplateLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.WHILE, p.parseWhileExpression)
 	p.registerPrefix(token.FOR, p.parseForExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
 
 	// Assign a infixExpression func to each token
 	p.infixParseFns = make(map[token.Type]infixParseFn)
@@ -84,16 +150,36 @@ func New(lexer *Lexer.Lexer) *Parser {
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.POW, p.parseInfixExpression)
-	p.registerInfix(token.MINUSMINUS, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
+	p.registerInfix(token.FLOOR_DIV, p.parseInfixExpression)
+	p.registerInfix(token.AMPERSAND, p.parseInfixExpression)
+	p.registerInfix(token.PIPE, p.parseInfixExpression)
+	p.registerInfix(token.PIPELINE, p.parseOwOExpression)
+	p.registerInfix(token.TILDE, p.parseInfixExpression)
+	p.registerInfix(token.SHL, p.parseInfixExpression)
+	p.registerInfix(token.SHR, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.LTE, p.parseInfixExpression)
 	p.registerInfix(token.GTE, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
+	p.registerInfix(token.NULLISH, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseFieldExpression)
+	p.registerInfix(token.OPTIONAL_DOT, p.parseOptionalFieldExpression)
+	p.registerInfix(token.OPTIONAL_LBRACKET, p.parseOptionalIndexExpression)
+	p.registerInfix(token.ASSIGN, p.parseBindExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseBindExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseBindExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseBindExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseBindExpression)
+	p.registerInfix(token.PLUSPLUS, p.parsePostfixExpression)
+	p.registerInfix(token.MINUSMINUS, p.parsePostfixExpression)
 
 	// set the value in the current token
 	p.nextToken()
@@ -105,36 +191,48 @@ func New(lexer *Lexer.Lexer) *Parser {
 
 // Retorna um wrape de ast.Identifier contendo o Token e o valor do Identifier
 func (p *Parser) parseIdentifier() ast.Expression {
-	if p.peekTokenIs(token.ASSIGN) {
-		binder := &ast.BindExpression{Token: p.currentToken, Left: p.currentToken.Literal}
+	return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+}
 
-		p.nextToken()
-		p.nextToken()
+// Ex: x :=: 1, lista[0] += 2, pessoa.nome :=: "Zooey"
+func (p *Parser) parseBindExpression(left ast.Expression) ast.Expression {
+	binder := &ast.BindExpression{Token: p.currentToken, Left: left, Operator: p.currentToken.Literal}
+
+	if !p.checkAssignable(left) {
+		return nil
+	}
 
-		binder.Value = p.parseExpression(LOWEST)
+	p.nextToken()
 
-		return binder
+	// A atribuição é associativa à direita: a :=: b :=: c
+	binder.Value = p.parseExpression(ASSIGN - 1)
 
-	} else if p.peekTokenIs(token.PLUSPLUS) || p.peekTokenIs(token.MINUSMINUS) {
-		// ex: i++
-		ident := p.currentToken
-		binder := &ast.BindExpression{Token: p.currentToken, Left: p.currentToken.Literal}
+	return binder
+}
 
-		p.nextToken()
-		// Parse the expression without recursion
-		binder.Value = &ast.InfixExpression{
-			Token: p.currentToken, // ++
-			Left: &ast.Identifier{
-				Token: ident, // the previous identifier
-				Value: ident.Literal,
-			},
-			Operator: p.currentToken.Literal, Right: nil,
-		}
+// Ex: i++, lista[0]--
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	binder := &ast.BindExpression{Token: p.currentToken, Left: left, Operator: p.currentToken.Literal}
+
+	if !p.checkAssignable(left) {
+		return nil
+	}
 
-		return binder
+	return binder
+}
+
+func (p *Parser) checkAssignable(target ast.Expression) bool {
+	switch target.(type) {
+	case nil:
+		return false
+	case *ast.Identifier, *ast.IndexExpression, *ast.FieldExpression:
+		return true
 	}
 
-	return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	d := diagnostic.Errorf(target.Pos(), target.End(), CodeInvalidAssignment, "cannot assign to %s", target.String())
+	d.Notes = []string{"only identifiers, index expressions and fields can be assigned"}
+	p.report(d)
+	return false
 }
 
 func (p *Parser) parseWhileExpression() ast.Expression {
@@ -161,38 +259,124 @@ func (p *Parser) parseWhileExpression() ast.Expression {
 }
 
 func (p *Parser) parseForExpression() ast.Expression {
+	if p.peekTokenIs(token.IDENT) {
+		return p.parseForInExpression()
+	}
+
 	expression := &ast.ForExpression{Token: p.currentToken}
 
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
 
-	if !p.expectPeek(token.OwO) {
+	expression.Init = p.parseForClause(token.SEMICOLON, true)
+	if expression.Init == nil {
 		return nil
 	}
 
-	expression.Identifier = p.ParseOwOStatement()
+	if !p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		expression.Condition = p.parseExpression(LOWEST)
+	}
 
-	if !p.expectPeek(token.IDENT) {
+	if !p.expectPeek(token.SEMICOLON) {
 		return nil
 	}
 
-	expression.Condition = p.parseExpression(LOWEST)
+	expression.Post = p.parseForClause(token.RPAREN, false)
+	if expression.Post == nil {
+		return nil
+	}
 
-	if !p.expectPeek(token.SEMICOLON) {
+	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
-	if !p.expectPeek(token.IDENT) {
+	expression.Consequence = p.parseBlockStatement()
+
+	return expression
+}
+
+// Lê uma lista de instruções separadas por vírgula até o token end, ex: owo i :=: 0, j :=: 10
+// Quando declaring é true, um "owo" no início declara também as atribuições seguintes da lista
+func (p *Parser) parseForClause(end token.Type, declaring bool) []ast.Statement {
+	statements := []ast.Statement{}
+
+	if p.peekTokenIs(end) {
+		p.nextToken()
+		return statements
+	}
+
+	p.nextToken()
+
+	var owo *token.Token
+	if declaring && p.currentTokenIs(token.OwO) {
+		tok := p.currentToken
+		owo = &tok
+	}
+
+	for {
+		var statement ast.Statement
+
+		switch {
+		case p.currentTokenIs(token.OwO):
+			binding := p.parseOwOBinding()
+			if binding == nil {
+				return nil
+			}
+			statement = binding
+		case owo != nil && p.currentTokenIs(token.IDENT) && p.peekTokenIs(token.ASSIGN):
+			binding := &ast.OwOStatement{Token: *owo}
+			binding.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+			p.nextToken()
+			p.nextToken()
+			binding.Value = p.parseExpression(LOWEST)
+			statement = binding
+		default:
+			statement = &ast.ExpressionStatement{Token: p.currentToken, Expression: p.parseExpression(LOWEST)}
+		}
+
+		statements = append(statements, statement)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+		p.nextToken()
+	}
+
+	if !p.expectPeek(end) {
 		return nil
 	}
 
-	expression.Aggregator = p.parseIdentifier()
+	return statements
+}
 
-	if !p.expectPeek(token.RPAREN) {
+// Ex: for nota in notas { ... } ou for chave, valor in hash { ... }
+func (p *Parser) parseForInExpression() ast.Expression {
+	expression := &ast.ForInExpression{Token: p.currentToken}
+
+	p.nextToken()
+	expression.Value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		expression.Key = expression.Value
+		expression.Value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	}
+
+	if !p.expectPeek(token.IN) {
 		return nil
 	}
 
+	p.nextToken()
+	expression.Iterable = p.parseExpression(LOWEST)
+
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
@@ -205,13 +389,41 @@ func (p *Parser) parseForExpression() ast.Expression {
 func (p *Parser) nextToken() {
 	p.currentToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+
+	switch p.currentToken.Type {
+	case token.LBRACE:
+		p.braces++
+	case token.RBRACE:
+		if p.braces > 0 {
+			p.braces--
+		}
+	}
+
+	// Erros do lexer entram na lista junto com os do parser. O que vem depois deles costuma ser
+	// consequência, então o parser entra em pânico como se o erro fosse dele
+	if errors := p.l.Errors(); len(errors) > p.lexerErrors {
+		p.errors = append(p.errors, errors[p.lexerErrors:]...)
+		p.lexerErrors = len(errors)
+		p.panicking = true
+	}
 }
 
 func (p *Parser) noPrefixParseFnError(t token.Type) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	if t == token.ILLEGAL {
+		p.report(p.illegalCharacter(p.currentToken))
+		return
+	}
+
+	d := diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeExpectedExpression, "expected an expression, got %s instead", describe(p.currentToken))
+	if t == token.DEFAULT {
+		d.Suggestions = []string{bindSuggestion}
+	}
+	p.report(d)
 }
 
+// O = sozinho só aparece em valores padrão; quem escreve x = 1 quase sempre queria :=:
+const bindSuggestion = "values are bound with :=:, as in owo x :=: 1"
+
 func (p *Parser) ParseProgram() *ast.Program {
 	// Inicializa a AST do nosso programa
 	program := &ast.Program{}
@@ -221,7 +433,10 @@ func (p *Parser) ParseProgram() *ast.Program {
 	for !p.currentTokenIs(token.EOF) {
 		// Olha o proximo token e "parseia" ele de acordo com o que ele representa
 		stmt := p.ParseStatement()
-		if stmt != nil {
+		if p.panicking {
+			// Descarta a instrução quebrada e recomeça na próxima
+			p.synchronize()
+		} else if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
 		p.nextToken()
@@ -236,6 +451,15 @@ func (p *Parser) ParseStatement() ast.Statement {
 		return p.ParseOwOStatement()
 	case token.RETURN:
 		return p.ParseReturnStatement()
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
+	case token.IDENT:
+		if p.peekTokenIs(token.COLON) {
+			return p.parseLabeledStatement()
+		}
+		return p.ParseExpressionStatement()
 	default:
 		// Expressões representam qualquer expressão depois do "="
 		// O principal cuidado que se deve ter é no momento de realizar operações que possuem precedencia
@@ -244,13 +468,45 @@ func (p *Parser) ParseStatement() ast.Statement {
 }
 
 func (p *Parser) ParseOwOStatement() *ast.OwOStatement {
+	statement := p.parseOwOBinding()
+	if statement == nil {
+		return nil
+	}
+
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+// owo nome :=: valor, owo [a, b] :=: valor ou owo q, r :=: valor, sem consumir o ";" seguinte
+func (p *Parser) parseOwOBinding() *ast.OwOStatement {
 	statement := &ast.OwOStatement{Token: p.currentToken}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		statement.Pattern = p.parsePattern()
+		if statement.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		statement.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
 	}
 
-	statement.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	if p.peekTokenIs(token.COMMA) {
+		first := statement.Pattern
+		if first == nil {
+			first = p.parseBindingPattern()
+		}
+		statement.Name, statement.Pattern = nil, p.parseTuplePattern(first)
+		if statement.Pattern == nil {
+			return nil
+		}
+	}
 
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
@@ -260,13 +516,82 @@ func (p *Parser) ParseOwOStatement() *ast.OwOStatement {
 
 	statement.Value = p.parseExpression(LOWEST)
 
-	for p.peekTokenIs(token.SEMICOLON) {
+	return statement
+}
+
+// Ex: outer: while(...) { ... break outer }
+func (p *Parser) parseLabeledStatement() ast.Statement {
+	label := p.currentToken.Literal
+
+	p.nextToken()
+
+	if !p.peekTokenIs(token.WHILE) && !p.peekTokenIs(token.FOR) {
+		d := diagnostic.Errorf(p.peekToken.Pos, p.peekToken.End, CodeInvalidLabel, "label %s must be followed by a loop, got %s instead", label, p.peekToken.Type)
+		d.Suggestions = []string{"labels can only be placed before while and for loops"}
+		p.report(d)
+		return nil
+	}
+
+	p.nextToken()
+
+	p.labels = append(p.labels, label)
+	statement := p.ParseExpressionStatement()
+	p.labels = p.labels[:len(p.labels)-1]
+
+	switch loop := statement.Expression.(type) {
+	case *ast.WhileExpression:
+		loop.Label = label
+	case *ast.ForExpression:
+		loop.Label = label
+	case *ast.ForInExpression:
+		loop.Label = label
+	}
+
+	return statement
+}
+
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	statement := &ast.BreakStatement{Token: p.currentToken}
+	statement.Label = p.parseLoopLabel()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	statement := &ast.ContinueStatement{Token: p.currentToken}
+	statement.Label = p.parseLoopLabel()
+
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
 	return statement
 }
 
+// Um identificador na mesma linha do break/continue é o label dele e precisa ser o de um laço em volta.
+// Numa linha nova ele começa outra instrução, então "break" seguido de "x :=: 1" continua funcionando
+func (p *Parser) parseLoopLabel() *ast.Identifier {
+	if !p.peekTokenIs(token.IDENT) || p.peekToken.Pos.Line != p.currentToken.Pos.Line {
+		return nil
+	}
+
+	p.nextToken()
+	label := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	for _, name := range p.labels {
+		if name == label.Value {
+			return label
+		}
+	}
+
+	p.report(diagnostic.Errorf(label.Pos(), label.End(), CodeInvalidLabel, "no enclosing loop labeled %s", label.Value))
+	return nil
+}
+
 func (p *Parser) ParseReturnStatement() *ast.ReturnStatement {
 	statement := &ast.ReturnStatement{Token: p.currentToken}
 
@@ -274,6 +599,14 @@ func (p *Parser) ParseReturnStatement() *ast.ReturnStatement {
 
 	statement.ReturnValue = p.parseExpression(LOWEST)
 
+	// return a, b devolve uma tupla
+	if statement.ReturnValue != nil && p.peekTokenIs(token.COMMA) {
+		statement.ReturnValue = p.parseTupleElements(statement.ReturnValue)
+		if statement.ReturnValue == nil {
+			return nil
+		}
+	}
+
 	for p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -346,17 +679,56 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// Ex: notas |> filtrar(aprovado) |> media, que é lido como ((notas |> filtrar(aprovado)) |> media)
+func (p *Parser) parseOwOExpression(left ast.Expression) ast.Expression {
+	expression := &ast.OwOExpression{Token: p.currentToken, Left: left}
+
+	precedence := p.currPrecedence()
+	p.nextToken()
+	expression.Right = p.parseExpression(precedence)
+	if expression.Right == nil {
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
 
+	// (a, b) é uma tupla
+	if exp != nil && p.peekTokenIs(token.COMMA) {
+		exp = p.parseTupleElements(exp)
+		if exp == nil {
+			return nil
+		}
+	}
+
 	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
 	return exp
 }
 
+// Os elementos de uma tupla depois do primeiro, que já foi lido, ex: a, b + 1, f(c)
+func (p *Parser) parseTupleElements(first ast.Expression) ast.Expression {
+	tuple := &ast.TupleLiteral{Elements: []ast.Expression{first}}
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		element := p.parseExpression(LOWEST)
+		if element == nil {
+			return nil
+		}
+		tuple.Elements = append(tuple.Elements, element)
+	}
+
+	return tuple
+}
+
 func (p *Parser) parseIfExpression() ast.Expression {
 	expression := &ast.IfExpression{Token: p.currentToken}
 
@@ -390,27 +762,36 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 
 	block.Statements = []ast.Statement{}
 
+	p.blocks = append(p.blocks, p.braces)
+	defer func() { p.blocks = p.blocks[:len(p.blocks)-1] }()
+
 	p.nextToken()
 
 	for !p.currentTokenIs(token.RBRACE) && !p.currentTokenIs(token.EOF) {
 		statement := p.ParseStatement()
-		if statement != nil {
+		if p.panicking {
+			// A recuperação pode parar em cima do } que fecha este bloco
+			if p.synchronize(); p.currentTokenIs(token.RBRACE) {
+				break
+			}
+		} else if statement != nil {
 			block.Statements = append(block.Statements, statement)
 		}
 		p.nextToken()
 	}
+	block.Rbrace = p.currentToken
 	return block
 }
 
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	lit := &ast.FunctionLiteral{Token: p.currentToken}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
+	// O nome é opcional: fn(x) { ... } é uma função anônima
+	if p.peekTokenIs(token.IDENT) {
+		p.nextToken()
+		lit.FnName = p.currentToken.Literal
 	}
 
-	lit.FnName = p.currentToken.Literal
-
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
@@ -426,28 +807,39 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return lit
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// Ex: fn(x, [a, b], {nome}, y = 10, ...resto) declara x, a, b, nome, y e resto
+func (p *Parser) parseFunctionParameters() []ast.Pattern {
+	parameters := []ast.Pattern{}
 
 	// Checa se é uma função vazia, fn()
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return identifiers
+		return parameters
 	}
 
 	// Captura o primeiro valor
 	p.nextToken()
 
-	// Guarda o primeiro valor num identifier
-	ident := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-	identifiers = append(identifiers, ident)
+	parameter := p.parseParameter()
+	if parameter == nil {
+		return nil
+	}
+	parameters = append(parameters, parameter)
 
 	// Se o proximo token for uma vigula
 	for p.peekTokenIs(token.COMMA) {
+		// O resto recebe todos os argumentos que sobraram, então não pode haver nada depois dele
+		if _, ok := parameter.(*ast.RestPattern); ok {
+			p.report(diagnostic.Errorf(parameter.Pos(), parameter.End(), CodeInvalidPattern, "a rest parameter must be the last one"))
+			return nil
+		}
 		p.nextToken()
 		p.nextToken()
-		ident := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-		identifiers = append(identifiers, ident)
+		parameter = p.parseParameter()
+		if parameter == nil {
+			return nil
+		}
+		parameters = append(parameters, parameter)
 	}
 
 	// Verifica se a função termina com ")"
@@ -455,16 +847,81 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 		return nil
 	}
 
-	return identifiers
+	return parameters
 }
 
 // Call of a function like "doSomething()""
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.currentToken, Function: function}
-	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	exp.Arguments = p.parseCallArguments()
+	exp.Rparen = p.currentToken
 	return exp
 }
 
+// Como parseExpressionList, mas aceita argumentos nomeados depois dos posicionais: f(1, y: 2)
+func (p *Parser) parseCallArguments() []ast.Expression {
+	arguments := []ast.Expression{}
+	named := map[string]bool{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return arguments
+	}
+
+	for {
+		p.nextToken()
+
+		if p.currentTokenIs(token.IDENT) && p.peekTokenIs(token.COLON) {
+			argument := p.parseNamedArgument()
+			if argument == nil {
+				return nil
+			}
+			if named[argument.Name.Value] {
+				p.report(diagnostic.Errorf(argument.Pos(), argument.Name.End(), CodeInvalidArgument, "argument %s is given more than once", argument.Name.Value))
+				return nil
+			}
+			named[argument.Name.Value] = true
+			arguments = append(arguments, argument)
+		} else {
+			argument := p.parseListElement()
+			if argument != nil && len(named) > 0 {
+				d := diagnostic.Errorf(argument.Pos(), argument.End(), CodeInvalidArgument, "positional argument follows a named argument")
+				d.Suggestions = []string{"pass the positional arguments first, as in f(1, y: 2)"}
+				p.report(d)
+				return nil
+			}
+			arguments = append(arguments, argument)
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return arguments
+}
+
+// Ex: y: 2
+func (p *Parser) parseNamedArgument() *ast.NamedArgument {
+	argument := &ast.NamedArgument{Name: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}}
+
+	p.nextToken()
+	argument.Token = p.currentToken
+
+	p.nextToken()
+	argument.Value = p.parseExpression(LOWEST)
+	if argument.Value == nil {
+		return nil
+	}
+
+	return argument
+}
+
 // Ex: list = [1,2,3] -> list[2]
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	exp := &ast.IndexExpression{Token: p.currentToken, Left: left}
@@ -476,16 +933,74 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
+	exp.Rbracket = p.currentToken
+
+	return exp
+}
+
+// Ex: pessoa.nome
+func (p *Parser) parseFieldExpression(left ast.Expression) ast.Expression {
+	exp := &ast.FieldExpression{Token: p.currentToken, Left: left}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	exp.Field = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	return exp
+}
+
+// Ex: pessoa?.nome
+func (p *Parser) parseOptionalFieldExpression(left ast.Expression) ast.Expression {
+	exp := &ast.OptionalFieldExpression{Token: p.currentToken, Left: left}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	exp.Field = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	return exp
+}
+
+// Ex: lista?[0]
+func (p *Parser) parseOptionalIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.OptionalIndexExpression{Token: p.currentToken, Left: left}
+
+	p.nextToken()
+
+	exp.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	exp.Rbracket = p.currentToken
 
 	return exp
 }
 
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.currentToken}
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
 
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
+
+		// {...padrao} copia os pares de outro hash
+		if p.currentTokenIs(token.ELLIPSIS) {
+			spread := p.parseSpreadExpression()
+			if spread == nil {
+				return nil
+			}
+			hash.Keys = append(hash.Keys, spread)
+			hash.Values = append(hash.Values, nil)
+
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
+
 		key := p.parseExpression(LOWEST)
 
 		if !p.expectPeek(token.COLON) {
@@ -494,7 +1009,8 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
-		hash.Pairs[key] = value
+		hash.Keys = append(hash.Keys, key)
+		hash.Values = append(hash.Values, value)
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -503,6 +1019,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	if !p.expectPeek(token.RBRACE) {
 		return nil
 	}
+	hash.Rbrace = p.currentToken
 	return hash
 
 }
@@ -510,11 +1027,19 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.LiteralInteger{Token: p.currentToken}
 
-	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
+	// O lexer já validou os dígitos e os _, então aqui só sobra o tamanho do valor
+	value, err := strconv.ParseInt(strings.Replace(p.currentToken.Literal, "_", "", -1), 0, 64)
+
+	// Literais maiores que int64 viram inteiros de precisão arbitrária
+	if errors.Is(err, strconv.ErrRange) {
+		if value, ok := new(big.Int).SetString(strings.Replace(p.currentToken.Literal, "_", "", -1), 0); ok {
+			lit.Big = value
+			return lit
+		}
+	}
 
 	if err != nil {
-		msg := fmt.Sprintf("Could not parse %q as integer", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.report(diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidNumber, "Could not parse %q as integer", p.currentToken.Literal))
 		return nil
 	}
 
@@ -527,11 +1052,15 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 func (p *Parser) parseFloatLiteral() ast.Expression {
 	lit := &ast.LiteralFloat{Token: p.currentToken}
 
-	value, err := strconv.ParseFloat(p.currentToken.Literal, 64)
+	value, err := strconv.ParseFloat(strings.Replace(p.currentToken.Literal, "_", "", -1), 64)
 
 	if err != nil {
-		msg := fmt.Sprintf("Could not parse %q as integer", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+		d := diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidNumber, "Could not parse %q as float", p.currentToken.Literal)
+		if errors.Is(err, strconv.ErrRange) {
+			d.Message = fmt.Sprintf("float literal %s is out of range", p.currentToken.Literal)
+			d.Notes = []string{fmt.Sprintf("the largest float is about %g", math.MaxFloat64)}
+		}
+		p.report(d)
 		return nil
 	}
 
@@ -540,14 +1069,86 @@ func (p *Parser) parseFloatLiteral() ast.Expression {
 	return lit
 
 }
+
+// Maior expoente aceito num Decimal, já que 1e1000000000d precisaria de um bilhão de dígitos
+const maxDecimalExponent = 10000
+
+// Ex: 12.50d vira 1250 com escala 2; 1.5e3d vira 1500 com escala 0
+func (p *Parser) parseDecimalLiteral() ast.Expression {
+	lit := &ast.LiteralDecimal{Token: p.currentToken}
+
+	digits := strings.Replace(strings.TrimSuffix(p.currentToken.Literal, "d"), "_", "", -1)
+
+	exponent := 0
+	if i := strings.IndexAny(digits, "eE"); i >= 0 {
+		value, err := strconv.Atoi(digits[i+1:])
+		if err != nil || value > maxDecimalExponent || value < -maxDecimalExponent {
+			d := diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidNumber, "decimal literal %s is out of range", p.currentToken.Literal)
+			d.Notes = []string{fmt.Sprintf("decimal exponents go from %d to %d", -maxDecimalExponent, maxDecimalExponent)}
+			p.report(d)
+			return nil
+		}
+		digits, exponent = digits[:i], value
+	}
+
+	if i := strings.Index(digits, "."); i >= 0 {
+		lit.Scale = len(digits) - i - 1
+		digits = digits[:i] + digits[i+1:]
+	}
+	lit.Scale -= exponent
+
+	lit.Unscaled, _ = new(big.Int).SetString(digits, 10)
+	if lit.Scale < 0 {
+		lit.Unscaled.Mul(lit.Unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-lit.Scale)), nil))
+		lit.Scale = 0
+	}
+
+	return lit
+}
+
 func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
 }
 
+// Ex: "média: ${media} (${passouDeAno(media)})"
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	template := &ast.TemplateLiteral{Token: p.currentToken}
+	p.appendTemplateText(template)
+
+	for {
+		p.nextToken()
+		template.Parts = append(template.Parts, p.parseExpression(LOWEST))
+
+		if !p.peekTokenIs(token.TEMPLATE_MIDDLE) && !p.peekTokenIs(token.TEMPLATE_TAIL) {
+			p.report(diagnostic.Errorf(p.peekToken.Pos, p.peekToken.End, CodeUnexpectedToken, "expected } to close the interpolation, got %s instead", describe(p.peekToken)))
+			return nil
+		}
+
+		p.nextToken()
+		p.appendTemplateText(template)
+
+		if p.currentTokenIs(token.TEMPLATE_TAIL) {
+			template.Tail = p.currentToken
+			return template
+		}
+	}
+}
+
+// Trechos vazios, como o que fica entre ${a}${b}, não entram no template
+func (p *Parser) appendTemplateText(template *ast.TemplateLiteral) {
+	if p.currentToken.Literal != "" {
+		template.Parts = append(template.Parts, &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal})
+	}
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.currentToken, Value: p.currentTokenIs(token.TRUE)}
 }
 
+func (p *Parser) parseNull() ast.Expression {
+	return &ast.NullLiteral{Token: p.currentToken}
+}
+
 func (p *Parser) currentTokenIs(t token.Type) bool {
 	return p.currentToken.Type == t
 }
@@ -566,13 +1167,81 @@ func (p *Parser) expectPeek(t token.Type) bool {
 	}
 }
 
-func (p *Parser) Errors() []string {
+func (p *Parser) Errors() []diagnostic.Diagnostic {
 	return p.errors
 }
 
+// Registra um diagnóstico, a não ser que o parser já esteja se recuperando de um erro anterior
+func (p *Parser) report(d diagnostic.Diagnostic) {
+	if p.panicking {
+		return
+	}
+
+	p.panicking = true
+	p.errors = append(p.errors, d)
+}
+
+// Pula tokens até o fim da instrução quebrada: para em um ";", no "}" que fecha o bloco atual ou logo
+// antes de uma palavra-chave que começa uma nova instrução. Chaves abertas no caminho, ou abertas pela
+// própria instrução antes do erro, como a de um hash, são puladas inteiras
+func (p *Parser) synchronize() {
+	p.panicking = false
+
+	level := 0
+	if len(p.blocks) != 0 {
+		level = p.blocks[len(p.blocks)-1]
+	}
+
+	for !p.currentTokenIs(token.EOF) {
+		switch p.currentToken.Type {
+		case token.RBRACE:
+			if p.braces < level {
+				return
+			}
+		case token.SEMICOLON:
+			if p.braces == level {
+				return
+			}
+		}
+
+		if p.braces == level {
+			switch p.peekToken.Type {
+			case token.EOF, token.RBRACE, token.OwO, token.RETURN, token.BREAK, token.CONTINUE,
+				token.IF, token.WHILE, token.FOR, token.FN, token.MATCH:
+				return
+			}
+		}
+
+		p.nextToken()
+	}
+}
+
 func (p *Parser) peekError(t token.Type) {
-	message := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
-	p.errors = append(p.errors, message)
+	if p.peekTokenIs(token.ILLEGAL) {
+		p.report(p.illegalCharacter(p.peekToken))
+		return
+	}
+
+	d := diagnostic.Errorf(p.peekToken.Pos, p.peekToken.End, CodeUnexpectedToken, "expected next token to be %s, got %s instead", t, describe(p.peekToken))
+	if t == token.ASSIGN && p.peekTokenIs(token.DEFAULT) {
+		d.Suggestions = []string{bindSuggestion}
+	}
+	p.report(d)
+}
+
+func (p *Parser) illegalCharacter(tok token.Token) diagnostic.Diagnostic {
+	return diagnostic.Errorf(tok.Pos, tok.End, CodeIllegalCharacter, "illegal character %q", tok.Literal)
+}
+
+// Nome de um token para as mensagens de erro; o fim do arquivo fica mais claro escrito por extenso
+func describe(tok token.Token) string {
+	switch tok.Type {
+	case token.EOF:
+		return "end of file"
+	case token.TEMPLATE_MIDDLE, token.TEMPLATE_TAIL:
+		return "}"
+	}
+	return string(tok.Type)
 }
 
 func (p *Parser) registerPrefix(tokenType token.Type, fn prefixParseFn) {
@@ -603,6 +1272,7 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.currentToken}
 
 	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Rbracket = p.currentToken
 
 	return array
 }
@@ -618,12 +1288,12 @@ func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
 	p.nextToken()
 
 	// Inicia o "parseamento" dos argumentos chamados
-	list = append(list, p.parseExpression(LOWEST))
+	list = append(list, p.parseListElement())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, p.parseListElement())
 	}
 
 	if !p.expectPeek(end) {
@@ -632,3 +1302,24 @@ func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
 
 	return list
 }
+
+// Um elemento de uma lista, que pode ser um ...espalhamento. Fora de listas, ... não é uma expressão
+func (p *Parser) parseListElement() ast.Expression {
+	if p.currentTokenIs(token.ELLIPSIS) {
+		return p.parseSpreadExpression()
+	}
+	return p.parseExpression(LOWEST)
+}
+
+// Ex: ...xs
+func (p *Parser) parseSpreadExpression() ast.Expression {
+	spread := &ast.SpreadExpression{Token: p.currentToken}
+
+	p.nextToken()
+	spread.Value = p.parseExpression(LOWEST)
+	if spread.Value == nil {
+		return nil
+	}
+
+	return spread
+}
@@ -1,11 +1,14 @@
 package parser
 
 import (
+	"fmt"
 	"log"
 	"testing"
 
+	ast "github.com/ZooeyLang/AST"
 	"github.com/ZooeyLang/Lexer"
 	token "github.com/ZooeyLang/Token"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParser_x(t *testing.T) {
@@ -24,3 +27,688 @@ func TestParser_x(t *testing.T) {
 	}
 
 }
+
+func TestParser_LogicalOperatorPrecedence(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "and binds tighter than or", input: "a || b && c", want: "(a || (b && c))"},
+		{name: "comparison binds tighter than and", input: "a == b && c < d", want: "((a == b) && (c < d))"},
+		{name: "or is left associative", input: "a || b || c", want: "((a || b) || c)"},
+		{name: "nullish binds looser than or", input: "a ?? b || c", want: "(a ?? (b || c))"},
+		{name: "nullish binds tighter than assignment", input: "x :=: a ?? b ?? c", want: "x :=: ((a ?? b) ?? c)"},
+		{name: "optional access chains like plain access", input: "a?.b.c?[i + 1](x) ?? null", want: "((a?.b.c?[(i + 1)])(x) ?? null)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_ArithmeticAndBitwisePrecedence(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "modulo and floor division bind like multiplication", input: "a + b % c ~/ d", want: "(a + ((b % c) ~/ d))"},
+		{name: "shift binds looser than sum", input: "a << b + c", want: "(a << (b + c))"},
+		{name: "and binds tighter than xor", input: "a ~ b & c", want: "(a ~ (b & c))"},
+		{name: "xor binds tighter than or", input: "a | b ~ c", want: "(a | (b ~ c))"},
+		{name: "bitwise binds tighter than comparison", input: "a & b == c | d", want: "((a & b) == (c | d))"},
+		{name: "prefix bitwise not", input: "~a & b", want: "((~a) & b)"},
+		{name: "pipeline is left associative", input: "notas |> filtrar(aprovado) |> media", want: "((notas |> filtrar(aprovado)) |> media)"},
+		{name: "pipeline binds looser than bitwise or", input: "a | b |> f | g", want: "((a | b) |> (f | g))"},
+		{name: "pipeline binds tighter than comparison", input: "a |> f == b |> g", want: "((a |> f) == (b |> g))"},
+		{name: "pipeline into an anonymous function", input: "x |> fn(n) { n * 2 }", want: "(x |> fn(n) (n * 2))"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_BreakAndContinue(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "plain break", input: "while(true) { break }", want: "whiletrue break;"},
+		{name: "plain continue", input: "while(true) { continue; }", want: "whiletrue continue;"},
+		{
+			name:  "break with the label of an enclosing loop",
+			input: "externo: while(true) { while(true) { break externo } }",
+			want:  "whiletrue whiletrue break externo;",
+		},
+		{
+			name:  "identifiers on the next line start a new statement",
+			input: "while(true) { break\nx }",
+			want:  "whiletrue break;x",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_LabelMustNameAnEnclosingLoop(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "misspelled label",
+			input: "owo outr :=: 0\nouter: for(owo i :=: 0; i < 3; i++) { for(;;) { break outr } }",
+			want:  []string{"2:55: error[P004]: no enclosing loop labeled outr"},
+		},
+		{
+			name:  "label of a loop that already ended",
+			input: "externo: while(true) { break }\nwhile(true) { continue externo }",
+			want:  []string{"2:24: error[P004]: no enclosing loop labeled externo"},
+		},
+		{
+			name:  "label outside of any loop",
+			input: "break x",
+			want:  []string{"1:7: error[P004]: no enclosing loop labeled x"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			p.ParseProgram()
+
+			got := []string{}
+			for _, d := range p.Errors() {
+				got = append(got, d.String())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParser_LabelMustPrecedeLoop(t *testing.T) {
+	source := "externo: x"
+	p := New(Lexer.New(source))
+	p.ParseProgram()
+
+	assert.Len(t, p.Errors(), 1)
+	assert.Equal(t, "1:10: error[P004]: label externo must be followed by a loop, got IDENT instead\nexterno: x\n         ^\n  = help: labels can only be placed before while and for loops", p.Errors()[0].Render(source))
+}
+
+func TestParser_ForInExpression(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "single variable", input: "for nota in notas { show(nota) }", want: "for nota in notas show(nota)"},
+		{name: "key and value", input: "for k, v in pessoa { show(v) }", want: "for k, v in pessoa show(v)"},
+		{name: "iterable expression", input: "for x in [1, 2] { x }", want: "for x in [1, 2] x"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_ForExpression(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{
+			name:  "classic loop",
+			input: "for(owo i :=: 0; i < 10; i++) { x }",
+			want:  "for(owo i = 0; (i < 10); i++) x",
+		},
+		{
+			name:  "empty clauses",
+			input: "for(;;) { x }",
+			want:  "for(; ; ) x",
+		},
+		{
+			name:  "multi-variable init declares every binding",
+			input: "for(owo i :=: 0, j :=: 10; i < j; i++, j--) { x }",
+			want:  "for(owo i = 0, owo j = 10; (i < j); i++, j--) x",
+		},
+		{
+			name:  "condition and post do not need to start with an identifier",
+			input: "for(; 0 < n; f(n)) { x }",
+			want:  "for(; (0 < n); f(n)) x",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_BindExpression(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []test{
+		{name: "identifier", input: "x :=: 1 + 2", want: "x :=: (1 + 2)"},
+		{name: "index target", input: "notas[i] :=: 10", want: "(notas[i]) :=: 10"},
+		{name: "field target", input: "pessoa.nome :=: \"Zooey\"", want: "pessoa.nome :=: Zooey"},
+		{name: "compound operator", input: "total += preco * 2", want: "total += (preco * 2)"},
+		{name: "postfix on an element", input: "contagem[k]++", want: "(contagem[k])++"},
+		{name: "assignment is right associative", input: "a :=: b += 1", want: "a :=: b += 1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_InvalidAssignmentTarget(t *testing.T) {
+	source := "a + b :=: 1"
+	p := New(Lexer.New(source))
+	p.ParseProgram()
+
+	assert.Len(t, p.Errors(), 1)
+	assert.Equal(t, "1:1: error[P003]: cannot assign to (a + b)\na + b :=: 1\n^^^^^\n  = note: only identifiers, index expressions and fields can be assigned", p.Errors()[0].Render(source))
+}
+
+func TestParser_ErrorsPointAtTheSource(t *testing.T) {
+	source := "owo media :=: 7\n\twhile media > 5 { show(media) }"
+	p := New(Lexer.NewFile("notas.zooey", source))
+	p.ParseProgram()
+
+	assert.Equal(t, "notas.zooey:2:8: error[P001]: expected next token to be (, got IDENT instead\n\twhile media > 5 { show(media) }\n\t      ^^^^^", p.Errors()[0].Render(source))
+}
+
+func TestParser_NodePositions(t *testing.T) {
+	p := New(Lexer.New("owo x :=: 1\nx :=: soma(x,\n  2)"))
+	program := p.ParseProgram()
+	assert.Empty(t, p.Errors())
+
+	bind := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.BindExpression)
+
+	assert.Equal(t, "2:1", bind.Pos().String())
+	assert.Equal(t, "3:5", bind.End().String())
+	assert.Equal(t, "2:7", bind.Value.Pos().String())
+
+	p = New(Lexer.New("xs\n  |> f(1)"))
+	program = p.ParseProgram()
+	assert.Empty(t, p.Errors())
+
+	pipeline := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.OwOExpression)
+
+	assert.Equal(t, "1:1", pipeline.Pos().String())
+	assert.Equal(t, "2:10", pipeline.End().String())
+}
+
+func TestParser_RecoversAtStatementBoundaries(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "one mistake yields one error",
+			input: "owo x :=: (1 + ;\nowo y :=: 2",
+			want:  []string{"1:16: error[P002]: expected an expression, got ; instead"},
+		},
+		{
+			name:  "each broken statement reports once",
+			input: "owo :=: 1\nshow(1)\nowo z :=: ]",
+			want: []string{
+				"1:5: error[P001]: expected next token to be IDENT, got ASSIGN instead",
+				"3:11: error[P002]: expected an expression, got ] instead",
+			},
+		},
+		{
+			name:  "errors inside a block resync at the block's statements",
+			input: "fn f() {\n\towo a :=: )\n\towo b :=: 1 +\n}\nf()",
+			want: []string{
+				"2:12: error[P002]: expected an expression, got ) instead",
+				"4:1: error[P002]: expected an expression, got } instead",
+			},
+		},
+		{
+			name:  "a broken loop header skips its whole body",
+			input: "while x > 1 { x :=: x - 1; owo y :=: }\nshow(x)",
+			want:  []string{"1:7: error[P001]: expected next token to be (, got IDENT instead"},
+		},
+		{
+			name:  "single equals suggests the bind operator",
+			input: "owo x = 1",
+			want:  []string{"1:7: error[P001]: expected next token to be ASSIGN, got = instead"},
+		},
+		{
+			name:  "lexer errors are reported without the cascade after them",
+			input: "owo x :=: 1\nshow(x /* fim",
+			want:  []string{"2:8: error[L001]: unterminated block comment"},
+		},
+		{
+			name:  "a broken hash literal resyncs after its closing brace",
+			input: "owo h :=: {\"a\" 1}; show(h)\nfn f() { owo h :=: {\"a\" 1}; h }\nf()",
+			want: []string{
+				"1:16: error[P001]: expected next token to be :, got INT instead",
+				"2:25: error[P001]: expected next token to be :, got INT instead",
+			},
+		},
+		{
+			name:  "a broken match arm resyncs after the match",
+			input: "match 1 { 1 => , _ => 2 }\nshow(1)",
+			want:  []string{"1:16: error[P002]: expected an expression, got , instead"},
+		},
+		{
+			name:  "a broken hash key resyncs after the hash",
+			input: "{(1 2): \"a\"}\nshow(1)",
+			want:  []string{"1:5: error[P001]: expected next token to be ), got INT instead"},
+		},
+		{
+			name:  "unterminated call at the end of the file",
+			input: "show(1, 2",
+			want:  []string{"1:10: error[P001]: expected next token to be ), got end of file instead"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			p.ParseProgram()
+
+			got := []string{}
+			for _, d := range p.Errors() {
+				got = append(got, d.String())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParser_RecoveredStatementsAreKept(t *testing.T) {
+	p := New(Lexer.New("owo a :=: 1; owo b :=: * 2; owo c :=: 3"))
+	program := p.ParseProgram()
+
+	assert.Len(t, p.Errors(), 1)
+	assert.Equal(t, "owo a = 1;owo c = 3;", program.String())
+
+	p = New(Lexer.New("owo x = 1"))
+	p.ParseProgram()
+	assert.Equal(t, []string{"values are bound with :=:, as in owo x :=: 1"}, p.Errors()[0].Suggestions)
+
+	p = New(Lexer.New("x = 1"))
+	p.ParseProgram()
+	assert.Equal(t, "1:3: error[P002]: expected an expression, got = instead", p.Errors()[0].String())
+	assert.Equal(t, []string{"values are bound with :=:, as in owo x :=: 1"}, p.Errors()[0].Suggestions)
+}
+
+func TestParser_TemplateLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "text and expressions", input: `"média: ${media} (${passou(media)})"`, want: `"média: ${media} (${passou(media)})"`},
+		{name: "expression only", input: `"${a + b}"`, want: `"${(a + b)}"`},
+		{name: "empty interpolation", input: `"${}"`, wantErr: "1:4: error[P002]: expected an expression, got } instead"},
+		{name: "unclosed interpolation", input: `"${a b}"`, wantErr: "1:6: error[P001]: expected } to close the interpolation, got IDENT instead"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			if tc.wantErr != "" {
+				assert.Equal(t, tc.wantErr, p.Errors()[0].String())
+				return
+			}
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_NumberLiterals(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{input: "1_000_000", want: "1000000"},
+		{input: "0xFF + 0b1010 + 0o17", want: "((255 + 10) + 15)"},
+		{input: "9223372036854775807", want: "9223372036854775807"},
+		{input: "9223372036854775808", want: "big 9223372036854775808"},
+		{input: "0x1_0000_0000_0000_0000", want: "big 18446744073709551616"},
+		{input: "12.50d", want: "decimal 1250 scale 2"},
+		{input: "1_000.5e2d", want: "decimal 100050 scale 0"},
+		{input: "25e-3d", want: "decimal 25 scale 3"},
+		{input: "1e20000d", wantErr: "1:1: error[P005]: decimal literal 1e20000d is out of range"},
+		{input: "1e400", wantErr: "1:1: error[P005]: float literal 1e400 is out of range"},
+		{input: "owo x :=: 0b2 + 1", wantErr: "1:13: error[L004]: invalid digit '2' in binary literal"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			if tc.wantErr != "" {
+				assert.Len(t, p.Errors(), 1)
+				assert.Equal(t, tc.wantErr, p.Errors()[0].String())
+				return
+			}
+
+			assert.Empty(t, p.Errors())
+			statement := program.Statements[0].(*ast.ExpressionStatement)
+			assert.Equal(t, tc.want, fmt.Sprint(constantValue(statement.Expression)))
+		})
+	}
+}
+
+// Troca os literais inteiros pelos seus valores, para conferir como o prefixo e os _ foram lidos
+func constantValue(exp ast.Expression) string {
+	switch exp := exp.(type) {
+	case *ast.LiteralInteger:
+		if exp.Big != nil {
+			return "big " + exp.Big.String()
+		}
+		return fmt.Sprint(exp.Value)
+	case *ast.LiteralDecimal:
+		return fmt.Sprintf("decimal %s scale %d", exp.Unscaled, exp.Scale)
+	case *ast.InfixExpression:
+		return "(" + constantValue(exp.Left) + " " + exp.Operator + " " + constantValue(exp.Right) + ")"
+	}
+	return exp.String()
+}
+
+func TestParser_MatchExpression(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "literals and wildcard", input: `match x { 0 => "zero", -1 => "menos um", "a" => 1, true => 2, null => 3, _ => 4 }`, want: `match x { 0 => zero, (-1) => menos um, "a" => 1, true => 2, null => 3, _ => 4 }`},
+		{name: "ranges", input: "match nota { 0..4.9 => 1, 5..10 => 2 }", want: "match nota { 0..4.9 => 1, 5..10 => 2 }"},
+		{name: "array patterns", input: "match xs { [] => 0, [x] => x, [primeiro, ...resto] => resto, [..._, ultimo] => ultimo }", want: "match xs { [] => 0, [x] => x, [primeiro, ...resto] => resto, [..., ultimo] => ultimo }"},
+		{name: "hash patterns", input: `match forma { {"tipo": "circulo", "raio": r} => r, {lado: l: float, 1: um, nome} => l }`, want: `match forma { {"tipo": "circulo", "raio": r} => r, {"lado": l: float, 1: um, "nome": nome} => l }`},
+		{name: "type patterns", input: "match v { n: int => n, _: fn => 0, s: string => s }", want: "match v { n: int => n, _: fn => 0, s: string => s }"},
+		{name: "guards", input: "match n { x if x > 0 && x < 10 => x, _ => 0 }", want: "match n { x if ((x > 0) && (x < 10)) => x, _ => 0 }"},
+		{name: "block arms need no comma", input: "match n { 1 => { show(n); n } _ => 0, }", want: "match n { 1 => show(n)n, _ => 0 }"},
+		{name: "match is an expression", input: "owo r :=: match n { _ => 1 } + 1;", want: "owo r = (match n { _ => 1 } + 1);"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_MatchErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "match x { 1 + 2 => 3 }", want: "1:13: error[P001]: expected next token to be =>, got + instead\nmatch x { 1 + 2 => 3 }\n            ^"},
+		{input: "match x { (a) => 1 }", want: "1:11: error[P007]: expected a pattern, got ( instead\nmatch x { (a) => 1 }\n          ^"},
+		{input: "match x { n: inteiro => n }", want: "1:14: error[P007]: unknown type inteiro in pattern\nmatch x { n: inteiro => n }\n             ^^^^^^^\n  = note: the types are array, bool, decimal, float, fn, hash, int, null, string, tuple"},
+		{input: "match x { [...a, ...b] => a }", want: "1:18: error[P007]: an array pattern can only have one ...rest\nmatch x { [...a, ...b] => a }\n                 ^^^"},
+		{input: "match x { 1 => 2 3 => 4 }", want: "1:18: error[P001]: expected next token to be ,, got INT instead\nmatch x { 1 => 2 3 => 4 }\n                 ^"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			p.ParseProgram()
+
+			assert.NotEmpty(t, p.Errors())
+			assert.Equal(t, tc.want, p.Errors()[0].Render(tc.input))
+		})
+	}
+}
+
+func TestParser_Destructuring(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "array", input: "owo [a, b, ...resto] :=: xs", want: "owo [a, b, ...resto] = xs;"},
+		{name: "hash with rename", input: "owo {nome, idade: anos} :=: pessoa", want: `owo {"nome": nome, "idade": anos} = pessoa;`},
+		{name: "nesting and defaults", input: `owo {dados: [x, y = 0], "tipo": t = "ponto"} :=: p`, want: `owo {"dados": [x, y = 0], "tipo": t = ponto} = p;`},
+		{name: "defaults are full expressions", input: "owo [a, b = a * 2 + 1] :=: xs", want: "owo [a, b = ((a * 2) + 1)] = xs;"},
+		{name: "parameters", input: "fn area({largura, altura: a = 1}, [x, _]) { largura * a }", want: `fn area({"largura": largura, "altura": a = 1}, [x, _]) (largura * a)`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_DestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "owo [a, 1 + 2] :=: xs", want: "1:11: error[P001]: expected next token to be ,, got + instead"},
+		{input: "owo {nome: } :=: p", want: "1:12: error[P007]: expected a pattern, got } instead"},
+		{input: "owo {(a)} :=: p", want: "1:6: error[P007]: expected a key in hash pattern, got ( instead"},
+		{input: "fn f(1) { }", want: "1:6: error[P007]: expected a parameter name or pattern, got INT instead"},
+		{input: "owo [a] = xs", want: "1:9: error[P001]: expected next token to be ASSIGN, got = instead"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			p.ParseProgram()
+
+			assert.NotEmpty(t, p.Errors())
+			assert.Equal(t, tc.want, p.Errors()[0].String())
+		})
+	}
+}
+
+func TestParser_FunctionArguments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "default parameters", input: "fn f(x, y = 10, z: int = 1 + 1) { x }", want: "fn f(x, y = 10, z: int = (1 + 1)) x"},
+		{name: "rest parameter", input: "fn f(x, ...resto) { x }", want: "fn f(x, ...resto) x"},
+		{name: "unnamed rest parameter", input: "fn(...) { 1 }", want: "fn(...) 1"},
+		{name: "named arguments", input: "f(1, y: 2 * 3, z: g(w: 1))", want: "f(1, y: (2 * 3), z: g(w: 1))"},
+		{name: "named argument in a nested expression", input: "f(x: a ?? b) + 1", want: "(f(x: (a ?? b)) + 1)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_FunctionArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "fn f(...xs, y) { }", want: "1:6: error[P007]: a rest parameter must be the last one"},
+		{input: "fn f(...xs = []) { }", want: "1:12: error[P001]: expected next token to be ), got = instead"},
+		{input: "f(x: 1, 2)", want: "1:9: error[P008]: positional argument follows a named argument"},
+		{input: "f(x: 1, x: 2)", want: "1:9: error[P008]: argument x is given more than once"},
+		{input: "[x: 1]", want: "1:3: error[P001]: expected next token to be ], got : instead"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			p.ParseProgram()
+
+			assert.NotEmpty(t, p.Errors())
+			assert.Equal(t, tc.want, p.Errors()[0].String())
+		})
+	}
+}
+
+func TestParser_Spread(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "call", input: "f(...args)", want: "f(...args)"},
+		{name: "call with other arguments", input: "f(1, ...a + b, y: 2)", want: "f(1, ...(a + b), y: 2)"},
+		{name: "array", input: "[...a, x, ...b]", want: "[...a, x, ...b]"},
+		{name: "hash", input: `{...padrao, "k": v, ...extra}`, want: "{...padrao, k:v, ...extra}"},
+		{name: "hash keeps the written order", input: `{"b": 1, "a": 2, "b": 3}`, want: "{b:1, a:2, b:3}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_SpreadErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "...xs", want: "1:1: error[P002]: expected an expression, got ... instead"},
+		{input: "owo x :=: 1 + ...xs", want: "1:15: error[P002]: expected an expression, got ... instead"},
+		{input: "[...]", want: "1:5: error[P002]: expected an expression, got ] instead"},
+		{input: "f(x: 1, ...xs)", want: "1:9: error[P008]: positional argument follows a named argument"},
+		{input: `{...a: 1}`, want: "1:6: error[P001]: expected next token to be ,, got : instead"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			p.ParseProgram()
+
+			assert.NotEmpty(t, p.Errors())
+			assert.Equal(t, tc.want, p.Errors()[0].String())
+		})
+	}
+}
+
+func TestParser_Tuples(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "return a tuple", input: "return a, b + 1, f(c, d)", want: "{ return (a, (b + 1), f(c, d)) }"},
+		{name: "return a single value", input: "return a", want: "{ return a }"},
+		{name: "destructure a tuple", input: "owo q, r :=: divmod(7, 2)", want: "owo q, r = divmod(7, 2);"},
+		{name: "destructure with patterns", input: "owo _, [x, y], {nome}, n: int, ...resto :=: t", want: `owo _, [x, y], {"nome": nome}, n: int, ...resto = t;`},
+		{name: "destructure with a default", input: "owo a, b = 0 :=: t", want: "owo a, b = 0 = t;"},
+		{name: "tuple type pattern", input: "match t { _: tuple => 1 }", want: "match t { _: tuple => 1 }"},
+		{name: "tuple literal", input: "(a, b + 1, (c, d))", want: "(a, (b + 1), (c, d))"},
+		{name: "parentheses around one value are a group", input: "(a)", want: "a"},
+		{name: "tuple literal as a hash key", input: `{(0, 0): "origem"}`, want: "{(0, 0):origem}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			program := p.ParseProgram()
+
+			assert.Empty(t, p.Errors())
+			assert.Equal(t, tc.want, program.String())
+		})
+	}
+}
+
+func TestParser_TupleErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "return a, ", want: "1:11: error[P002]: expected an expression, got end of file instead"},
+		{input: "(a, )", want: "1:5: error[P002]: expected an expression, got ) instead"},
+		{input: "(a, b", want: "1:6: error[P001]: expected next token to be ), got end of file instead"},
+		{input: "owo a, 1 :=: t", want: "1:8: error[P007]: expected a parameter name or pattern, got INT instead"},
+		{input: "owo ...a, b :=: t", want: "1:5: error[P001]: expected next token to be IDENT, got ... instead"},
+		{input: "owo a, ...b, ...c :=: t", want: "1:14: error[P007]: a tuple pattern can only have one ...rest"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(Lexer.New(tc.input))
+			p.ParseProgram()
+
+			assert.NotEmpty(t, p.Errors())
+			assert.Equal(t, tc.want, p.Errors()[0].String())
+		})
+	}
+}
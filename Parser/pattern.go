@@ -0,0 +1,350 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	ast "github.com/ZooeyLang/AST"
+	diagnostic "github.com/ZooeyLang/Diagnostic"
+	token "github.com/ZooeyLang/Token"
+)
+
+// Tipos aceitos num padrão de tipo, ex: n: int
+var patternTypes = []string{"array", "bool", "decimal", "float", "fn", "hash", "int", "null", "string", "tuple"}
+
+// Ex: match x { 0 => "zero", n if n < 0 => "negativo", _ => { show(x); "positivo" } }
+//
+// O corpo de um braço é uma expressão ou um bloco { }; depois de um bloco a vírgula é opcional.
+// Para devolver um hash, escreva o hash dentro de um bloco: _ => { {"a": 1} }
+func (p *Parser) parseMatchExpression() ast.Expression {
+	match := &ast.MatchExpression{Token: p.currentToken}
+
+	p.nextToken()
+	match.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		match.Arms = append(match.Arms, arm)
+
+		if _, block := arm.Body.(*ast.BlockStatement); block && !p.peekTokenIs(token.COMMA) {
+			continue
+		}
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	match.Rbrace = p.currentToken
+
+	return match
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{Pattern: p.parsePattern()}
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+	p.nextToken()
+
+	if p.currentTokenIs(token.LBRACE) {
+		arm.Body = p.parseBlockStatement()
+		return arm
+	}
+
+	body := p.parseExpression(LOWEST)
+	if body == nil {
+		return nil
+	}
+	arm.Body = body
+
+	return arm
+}
+
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.currentToken.Type {
+	case token.IDENT:
+		return p.parseBindingPattern()
+	case token.INT, token.FLOAT, token.DECIMAL, token.STRING, token.TRUE, token.FALSE, token.NULL, token.MINUS:
+		return p.parseLiteralPattern()
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	case token.ILLEGAL:
+		p.report(p.illegalCharacter(p.currentToken))
+		return nil
+	}
+
+	p.report(diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidPattern, "expected a pattern, got %s instead", describe(p.currentToken)))
+	return nil
+}
+
+// Ex: x, _, n: int, _: string
+func (p *Parser) parseBindingPattern() ast.Pattern {
+	var name *ast.Identifier
+	if p.currentToken.Literal != "_" {
+		name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	}
+
+	if !p.peekTokenIs(token.COLON) {
+		if name == nil {
+			return &ast.WildcardPattern{Token: p.currentToken}
+		}
+		return &ast.BindingPattern{Name: name}
+	}
+
+	p.nextToken()
+	pattern := &ast.TypePattern{Token: p.currentToken, Name: name}
+
+	// fn e null são palavras-chave, mas aqui são nomes de tipos
+	p.nextToken()
+	pattern.Type = p.currentToken
+	for _, name := range patternTypes {
+		if p.currentToken.Literal == name {
+			return pattern
+		}
+	}
+
+	d := diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidPattern, "unknown type %s in pattern", describeLiteral(p.currentToken))
+	d.Notes = []string{fmt.Sprintf("the types are %s", strings.Join(patternTypes, ", "))}
+	p.report(d)
+	return nil
+}
+
+// Ex: 42, -1.5, "zooey", 1..9
+func (p *Parser) parseLiteralPattern() ast.Pattern {
+	low := p.parsePatternLiteral()
+	if low == nil {
+		return nil
+	}
+
+	if !p.peekTokenIs(token.DOTDOT) {
+		return &ast.LiteralPattern{Value: low}
+	}
+
+	p.nextToken()
+	pattern := &ast.RangePattern{Token: p.currentToken, Low: low}
+
+	p.nextToken()
+	pattern.High = p.parsePatternLiteral()
+	if pattern.High == nil {
+		return nil
+	}
+
+	return pattern
+}
+
+// Um literal dentro de um padrão; números negativos viram um PrefixExpression, como no resto da linguagem
+func (p *Parser) parsePatternLiteral() ast.Expression {
+	switch p.currentToken.Type {
+	case token.MINUS:
+		exp := &ast.PrefixExpression{Token: p.currentToken, Operator: p.currentToken.Literal}
+		if !p.peekTokenIs(token.INT) && !p.peekTokenIs(token.FLOAT) && !p.peekTokenIs(token.DECIMAL) {
+			p.report(diagnostic.Errorf(p.peekToken.Pos, p.peekToken.End, CodeInvalidPattern, "expected a number after - in pattern, got %s instead", describe(p.peekToken)))
+			return nil
+		}
+		p.nextToken()
+		exp.Right = p.prefixParseFns[p.currentToken.Type]()
+		if exp.Right == nil {
+			return nil
+		}
+		return exp
+	case token.INT, token.FLOAT, token.DECIMAL, token.STRING, token.TRUE, token.FALSE, token.NULL:
+		return p.prefixParseFns[p.currentToken.Type]()
+	}
+
+	p.report(diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidPattern, "expected a literal in pattern, got %s instead", describe(p.currentToken)))
+	return nil
+}
+
+// Ex: [], [x, y], [primeiro, ...resto], [..._, ultimo]
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.currentToken}
+	rest := false
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		var element ast.Pattern
+		if p.currentTokenIs(token.ELLIPSIS) {
+			if rest {
+				p.report(diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidPattern, "an array pattern can only have one ...rest"))
+				return nil
+			}
+			rest = true
+			element = p.parseRestPattern()
+		} else {
+			element = p.parseDefaultPattern(p.parsePattern())
+		}
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	pattern.Rbracket = p.currentToken
+
+	return pattern
+}
+
+func (p *Parser) parseRestPattern() ast.Pattern {
+	pattern := &ast.RestPattern{Token: p.currentToken}
+
+	if p.peekTokenIs(token.IDENT) {
+		p.nextToken()
+		if p.currentToken.Literal != "_" {
+			pattern.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		}
+	}
+
+	return pattern
+}
+
+// Ex: {"tipo": "circulo", "raio": r}, {nome, idade: anos = 18}
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		var key ast.Expression
+		var value ast.Pattern
+		switch p.currentToken.Type {
+		case token.STRING, token.INT:
+			key = p.prefixParseFns[p.currentToken.Type]()
+			if key == nil || !p.expectPeek(token.COLON) {
+				return nil
+			}
+			p.nextToken()
+			value = p.parsePattern()
+		case token.IDENT:
+			key = &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+			if p.peekTokenIs(token.COLON) {
+				p.nextToken()
+				p.nextToken()
+				value = p.parsePattern()
+			} else {
+				// {nome} declara nome com o valor da chave "nome"
+				value = &ast.BindingPattern{Name: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}}
+			}
+		default:
+			p.report(diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidPattern, "expected a key in hash pattern, got %s instead", describe(p.currentToken)))
+			return nil
+		}
+		value = p.parseDefaultPattern(value)
+		if value == nil {
+			return nil
+		}
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	pattern.Rbrace = p.currentToken
+
+	return pattern
+}
+
+// Ex: q, r e _, ...resto em owo q, r :=: valor. first é o padrão antes da primeira vírgula
+func (p *Parser) parseTuplePattern(first ast.Pattern) ast.Pattern {
+	pattern := &ast.TuplePattern{Elements: []ast.Pattern{first}}
+	rest := false
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+
+		element := p.parseParameter()
+		if element == nil {
+			return nil
+		}
+		if _, ok := element.(*ast.RestPattern); ok {
+			if rest {
+				p.report(diagnostic.Errorf(element.Pos(), element.End(), CodeInvalidPattern, "a tuple pattern can only have one ...rest"))
+				return nil
+			}
+			rest = true
+		}
+		pattern.Elements = append(pattern.Elements, element)
+	}
+
+	return pattern
+}
+
+// Ex: b = 0 em [a, b = 0]. Só os elementos de um array, os valores de um hash e os parâmetros podem ter valor padrão
+func (p *Parser) parseDefaultPattern(pattern ast.Pattern) ast.Pattern {
+	if pattern == nil || !p.peekTokenIs(token.DEFAULT) {
+		return pattern
+	}
+
+	p.nextToken()
+	def := &ast.DefaultPattern{Token: p.currentToken, Pattern: pattern}
+
+	// O valor padrão não pode ser um :=:, que em owo a, b = 0 :=: t é o da declaração
+	p.nextToken()
+	def.Default = p.parseExpression(ASSIGN)
+	if def.Default == nil {
+		return nil
+	}
+
+	return def
+}
+
+// Ex: x, y = 10, ...resto. Só o último parâmetro pode ser um resto, que não tem valor padrão
+func (p *Parser) parseParameter() ast.Pattern {
+	if p.currentTokenIs(token.ELLIPSIS) {
+		return p.parseRestPattern()
+	}
+	return p.parseDefaultPattern(p.parseParameterPattern())
+}
+
+// Ex: x, [a, b], {nome, idade}. Os parâmetros aceitam os mesmos padrões que owo, e não literais ou faixas
+func (p *Parser) parseParameterPattern() ast.Pattern {
+	switch p.currentToken.Type {
+	case token.IDENT, token.LBRACKET, token.LBRACE:
+		return p.parsePattern()
+	case token.ILLEGAL:
+		p.report(p.illegalCharacter(p.currentToken))
+		return nil
+	}
+
+	p.report(diagnostic.Errorf(p.currentToken.Pos, p.currentToken.End, CodeInvalidPattern, "expected a parameter name or pattern, got %s instead", describe(p.currentToken)))
+	return nil
+}
+
+// Para nomes que o usuário escreveu, como o tipo em n: inteiro, o texto ajuda mais que o tipo do token
+func describeLiteral(tok token.Token) string {
+	if tok.Type == token.EOF {
+		return describe(tok)
+	}
+	return tok.Literal
+}
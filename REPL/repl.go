@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"strings"
 
+	diagnostic "github.com/ZooeyLang/Diagnostic"
 	evaluator "github.com/ZooeyLang/Evaluator"
 	lexer "github.com/ZooeyLang/Lexer"
 	object "github.com/ZooeyLang/Object"
 	parser "github.com/ZooeyLang/Parser"
+	resolver "github.com/ZooeyLang/Resolver"
 )
 
 // READ EVAL PRINT LOOP
@@ -19,6 +22,8 @@ func Start(in io.Reader, out io.Writer) {
 
 	scanner := bufio.NewScanner(in)
 	env := object.NewEnvironment()
+	// O resolver guarda as declarações globais das linhas anteriores, assim como o env
+	r := resolver.New(evaluator.BuiltinNames()...)
 
 	for {
 
@@ -36,11 +41,22 @@ func Start(in io.Reader, out io.Writer) {
 		program := p.ParseProgram()
 
 		if len(p.Errors()) != 0 {
-			printParserErrors(out, p.Errors())
+			printParserErrors(out, p.Errors(), line)
+			continue
+		}
+
+		r.Resolve(program)
+		if len(r.Errors()) != 0 {
+			printResolverErrors(out, r.Errors(), line)
 			continue
 		}
 
 		evaluated := evaluator.Eval(program, env)
+		if err, ok := evaluated.(*object.Error); ok {
+			io.WriteString(out, err.Report(line))
+			io.WriteString(out, "\n")
+			continue
+		}
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
@@ -62,11 +78,24 @@ const Mellus = `
 	░  ░   ░        ░         ░ ░     ░   
 `
 
-func printParserErrors(out io.Writer, errors []string) {
+func printParserErrors(out io.Writer, diagnostics []diagnostic.Diagnostic, source string) {
 	io.WriteString(out, Mellus)
 	io.WriteString(out, "Woops! We ran into some wrong business here!\n")
 	io.WriteString(out, " parser errors:\n")
-	for _, msg := range errors {
-		io.WriteString(out, "\t"+msg+"\n")
+	for _, d := range diagnostics {
+		// Indenta todas as linhas do diagnóstico, assim o ^ continua embaixo do trecho certo
+		for _, line := range strings.Split(d.Render(source), "\n") {
+			io.WriteString(out, "\t"+line+"\n")
+		}
+	}
+}
+
+func printResolverErrors(out io.Writer, diagnostics []diagnostic.Diagnostic, source string) {
+	io.WriteString(out, "Woops! Some names are not where you think they are!\n")
+	io.WriteString(out, " resolver errors:\n")
+	for _, d := range diagnostics {
+		for _, line := range strings.Split(d.Render(source), "\n") {
+			io.WriteString(out, "\t"+line+"\n")
+		}
 	}
 }
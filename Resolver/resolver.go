@@ -0,0 +1,347 @@
+package resolver
+
+import (
+	ast "github.com/ZooeyLang/AST"
+	diagnostic "github.com/ZooeyLang/Diagnostic"
+)
+
+// Códigos dos diagnósticos gerados pelo resolver
+const (
+	CodeUndefinedName        = "R001"
+	CodeUndeclaredAssignment = "R002"
+	CodeUsedBeforeDeclared   = "R003"
+)
+
+// O resolver percorre a AST antes da execução e liga cada identificador ao escopo em que ele foi declarado.
+// Os escopos daqui precisam espelhar exatamente os environments criados pelo evaluator:
+//   - o programa (environment global)
+//   - cada função, com os parâmetros e as variáveis do corpo
+//   - cada bloco { } de if, while e for
+//   - o laço for(init; cond; post), com as variáveis do init
+//   - cada volta do for x in ..., com as variáveis do laço
+//   - cada braço de um match, com as variáveis do padrão
+type Resolver struct {
+	scopes   []*scope
+	builtins map[string]bool
+
+	errors []diagnostic.Diagnostic
+}
+
+type scope struct {
+	names map[string]bool
+
+	// Se o escopo é o de uma função, cujo corpo só roda quando ela é chamada
+	function bool
+
+	// Usos de nomes que ainda não foram declarados em nenhum escopo visível.
+	// Eles são resolvidos quando o escopo fecha, o que permite chamar uma função declarada mais abaixo
+	pending []reference
+}
+
+type reference struct {
+	ident    *ast.Identifier
+	level    int  // Índice do escopo em que o identificador foi usado
+	function int  // Índice da função mais interna em volta do uso, ou -1 fora de funções
+	assign   bool // Se o uso é o alvo de uma atribuição
+}
+
+// Cria um resolver com o escopo global aberto; builtins são nomes que existem sem declaração
+func New(builtins ...string) *Resolver {
+	r := &Resolver{builtins: make(map[string]bool)}
+	for _, name := range builtins {
+		r.builtins[name] = true
+	}
+
+	r.scopes = []*scope{newScope()}
+
+	return r
+}
+
+func newScope() *scope {
+	return &scope{names: make(map[string]bool)}
+}
+
+// Resolve o programa inteiro. O escopo global continua aberto depois disso, então o mesmo resolver
+// pode ser usado em várias chamadas, como no REPL, onde cada linha é um programa
+func (r *Resolver) Resolve(program *ast.Program) {
+	r.errors = []diagnostic.Diagnostic{}
+
+	global := r.scopes[0]
+	declared := make(map[string]bool, len(global.names))
+	for name := range global.names {
+		declared[name] = true
+	}
+
+	for _, statement := range program.Statements {
+		r.resolve(statement)
+	}
+
+	for _, ref := range global.pending {
+		r.bindOrReport(ref, global, 0)
+	}
+	global.pending = nil
+
+	// Um programa com erros não é executado, então as suas declarações não devem valer para os próximos
+	if len(r.errors) != 0 {
+		global.names = declared
+	}
+}
+
+func (r *Resolver) Errors() []diagnostic.Diagnostic {
+	return r.errors
+}
+
+func (r *Resolver) resolve(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		r.resolveExpression(node.Expression)
+	case *ast.OwOStatement:
+		// Uma função atribuída a uma variável pode chamar a si mesma por ela.
+		// Num padrão, como owo [a] :=: fn() { ... }, os nomes só existem depois do valor
+		if _, ok := node.Value.(*ast.FunctionLiteral); ok && node.Name != nil {
+			r.declare(node.Name)
+			r.resolveExpression(node.Value)
+			return
+		}
+		r.resolveExpression(node.Value)
+		if node.Pattern != nil {
+			r.declarePattern(node.Pattern)
+			return
+		}
+		r.declare(node.Name)
+	case *ast.ReturnStatement:
+		r.resolveExpression(node.ReturnValue)
+	case *ast.BlockStatement:
+		r.beginScope()
+		r.resolveStatements(node.Statements)
+		r.endScope()
+	case *ast.BreakStatement, *ast.ContinueStatement:
+	}
+}
+
+func (r *Resolver) resolveStatements(statements []ast.Statement) {
+	for _, statement := range statements {
+		r.resolve(statement)
+	}
+}
+
+func (r *Resolver) resolveExpression(node ast.Expression) {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		r.reference(node, false)
+	case *ast.PrefixExpression:
+		r.resolveExpression(node.Right)
+	case *ast.InfixExpression:
+		r.resolveExpression(node.Left)
+		r.resolveExpression(node.Right)
+	case *ast.BindExpression:
+		if ident, ok := node.Left.(*ast.Identifier); ok {
+			r.reference(ident, true)
+		} else {
+			r.resolveExpression(node.Left)
+		}
+		r.resolveExpression(node.Value)
+	case *ast.IfExpression:
+		r.resolveExpression(node.Condition)
+		r.resolve(node.Consequence)
+		if node.Alternative != nil {
+			r.resolve(node.Alternative)
+		}
+	case *ast.WhileExpression:
+		r.resolveExpression(node.Condition)
+		r.resolve(node.Consequence)
+	case *ast.ForExpression:
+		r.beginScope()
+		r.resolveStatements(node.Init)
+		r.resolveExpression(node.Condition)
+		r.resolveStatements(node.Post)
+		r.resolve(node.Consequence)
+		r.endScope()
+	case *ast.ForInExpression:
+		r.resolveExpression(node.Iterable)
+		r.beginScope()
+		if node.Key != nil {
+			r.declare(node.Key)
+		}
+		r.declare(node.Value)
+		r.resolve(node.Consequence)
+		r.endScope()
+	case *ast.MatchExpression:
+		r.resolveExpression(node.Subject)
+		for _, arm := range node.Arms {
+			r.beginScope()
+			r.declarePattern(arm.Pattern)
+			r.resolveExpression(arm.Guard)
+			if block, ok := arm.Body.(*ast.BlockStatement); ok {
+				r.resolve(block)
+			} else {
+				r.resolveExpression(arm.Body.(ast.Expression))
+			}
+			r.endScope()
+		}
+	case *ast.FunctionLiteral:
+		if node.FnName != "" {
+			r.declareName(node.FnName)
+		}
+		r.beginScope()
+		r.scopes[len(r.scopes)-1].function = true
+		for _, param := range node.Parameters {
+			r.declarePattern(param)
+		}
+		r.resolveStatements(node.Body.Statements)
+		r.endScope()
+	case *ast.CallExpression:
+		r.resolveExpression(node.Function)
+		for _, arg := range node.Arguments {
+			r.resolveExpression(arg)
+		}
+	case *ast.TupleLiteral:
+		for _, element := range node.Elements {
+			r.resolveExpression(element)
+		}
+	case *ast.OwOExpression:
+		r.resolveExpression(node.Left)
+		r.resolveExpression(node.Right)
+	case *ast.SpreadExpression:
+		r.resolveExpression(node.Value)
+	case *ast.NamedArgument:
+		// O nome é o de um parâmetro da função chamada, e não uma variável
+		r.resolveExpression(node.Value)
+	case *ast.IndexExpression:
+		r.resolveExpression(node.Left)
+		r.resolveExpression(node.Index)
+	case *ast.FieldExpression:
+		r.resolveExpression(node.Left)
+	case *ast.OptionalIndexExpression:
+		r.resolveExpression(node.Left)
+		r.resolveExpression(node.Index)
+	case *ast.OptionalFieldExpression:
+		r.resolveExpression(node.Left)
+	case *ast.TemplateLiteral:
+		for _, part := range node.Parts {
+			r.resolveExpression(part)
+		}
+	case *ast.ArrayLiteral:
+		for _, element := range node.Elements {
+			r.resolveExpression(element)
+		}
+	case *ast.HashLiteral:
+		for i, key := range node.Keys {
+			r.resolveExpression(key)
+			r.resolveExpression(node.Values[i])
+		}
+	}
+}
+
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, newScope())
+}
+
+// Fecha o escopo atual, resolvendo os usos pendentes que ele declarou e repassando os outros ao escopo de fora
+func (r *Resolver) endScope() {
+	level := len(r.scopes) - 1
+	closing := r.scopes[level]
+	r.scopes = r.scopes[:level]
+
+	outer := r.scopes[level-1]
+	for _, ref := range closing.pending {
+		if closing.names[ref.ident.Value] {
+			r.bind(ref, level)
+			continue
+		}
+		outer.pending = append(outer.pending, ref)
+	}
+}
+
+func (r *Resolver) declare(ident *ast.Identifier) {
+	r.declareName(ident.Value)
+}
+
+// Declara as variáveis que um padrão cria, ex: [x, ...resto] declara x e resto
+func (r *Resolver) declarePattern(pattern ast.Pattern) {
+	switch pattern := pattern.(type) {
+	case *ast.BindingPattern:
+		r.declare(pattern.Name)
+	case *ast.TypePattern:
+		if pattern.Name != nil {
+			r.declare(pattern.Name)
+		}
+	case *ast.RestPattern:
+		if pattern.Name != nil {
+			r.declare(pattern.Name)
+		}
+	case *ast.ArrayPattern:
+		for _, element := range pattern.Elements {
+			r.declarePattern(element)
+		}
+	case *ast.TuplePattern:
+		for _, element := range pattern.Elements {
+			r.declarePattern(element)
+		}
+	case *ast.HashPattern:
+		for _, value := range pattern.Values {
+			r.declarePattern(value)
+		}
+	case *ast.DefaultPattern:
+		// O valor padrão enxerga as variáveis declaradas antes dele, como em [a, b = a]
+		r.resolveExpression(pattern.Default)
+		r.declarePattern(pattern.Pattern)
+	}
+}
+
+func (r *Resolver) declareName(name string) {
+	r.scopes[len(r.scopes)-1].names[name] = true
+}
+
+func (r *Resolver) reference(ident *ast.Identifier, assign bool) {
+	level := len(r.scopes) - 1
+
+	for i := level; i >= 0; i-- {
+		if r.scopes[i].names[ident.Value] {
+			ident.Binding = &ast.Binding{Depth: level - i}
+			return
+		}
+	}
+
+	function := -1
+	for i := level; i >= 0; i-- {
+		if r.scopes[i].function {
+			function = i
+			break
+		}
+	}
+
+	current := r.scopes[level]
+	current.pending = append(current.pending, reference{ident: ident, level: level, function: function, assign: assign})
+}
+
+func (r *Resolver) bindOrReport(ref reference, s *scope, level int) {
+	name := ref.ident.Value
+
+	switch {
+	case s.names[name]:
+		r.bind(ref, level)
+	case ref.assign:
+		d := diagnostic.Errorf(ref.ident.Pos(), ref.ident.End(), CodeUndeclaredAssignment, "cannot assign to undeclared identifier %s", name)
+		d.Suggestions = []string{"declare it first with owo " + name + " :=: ..."}
+		r.errors = append(r.errors, d)
+	case r.builtins[name]:
+		// Funções nativas continuam sendo buscadas dinamicamente pelo evaluator
+	default:
+		r.errors = append(r.errors, diagnostic.Errorf(ref.ident.Pos(), ref.ident.End(), CodeUndefinedName, "identifier not found: %s", name))
+	}
+}
+
+// Liga um uso a um nome declarado depois dele no escopo de índice level.
+// Isso só vale dentro de uma função aberta depois desse escopo, que só roda quando a declaração já aconteceu
+func (r *Resolver) bind(ref reference, level int) {
+	if ref.function <= level {
+		name := ref.ident.Value
+		d := diagnostic.Errorf(ref.ident.Pos(), ref.ident.End(), CodeUsedBeforeDeclared, "identifier %s used before its declaration", name)
+		d.Suggestions = []string{"move the declaration of " + name + " above this line"}
+		r.errors = append(r.errors, d)
+		return
+	}
+
+	ref.ident.Binding = &ast.Binding{Depth: ref.level - level}
+}
@@ -0,0 +1,212 @@
+package resolver
+
+import (
+	"testing"
+
+	ast "github.com/ZooeyLang/AST"
+	diagnostic "github.com/ZooeyLang/Diagnostic"
+	lexer "github.com/ZooeyLang/Lexer"
+	parser "github.com/ZooeyLang/Parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	assert.Empty(t, p.Errors(), "The program must parse without errors!")
+
+	return program
+}
+
+// Só as mensagens dos diagnósticos, para tabelas mais fáceis de ler
+func messages(diagnostics []diagnostic.Diagnostic) []string {
+	msgs := []string{}
+	for _, d := range diagnostics {
+		msgs = append(msgs, d.Message)
+	}
+	return msgs
+}
+
+func TestResolver_Errors(t *testing.T) {
+	type test struct {
+		name  string
+		input string
+		want  []string
+	}
+
+	tests := []test{
+		{
+			name:  "declared names resolve",
+			input: "owo x :=: 1; x + 1",
+			want:  []string{},
+		},
+		{
+			name:  "undefined names are reported",
+			input: "owo x :=: 1; x + y",
+			want:  []string{"identifier not found: y"},
+		},
+		{
+			name:  "assignment to an undeclared name is reported",
+			input: "fn f() { total :=: 1 }",
+			want:  []string{"cannot assign to undeclared identifier total"},
+		},
+		{
+			name:  "builtins are always declared",
+			input: "show(len([1]))",
+			want:  []string{},
+		},
+		{
+			name:  "functions can be called before they are declared",
+			input: "fn a() { b() }; fn b() { 1 }",
+			want:  []string{},
+		},
+		{
+			name:  "names cannot be read before their declaration outside of a function",
+			input: "show(y); owo y :=: 1; if true { z }; owo z :=: 2; for v in [1] { w :=: v }; owo w :=: 3",
+			want: []string{
+				"identifier y used before its declaration",
+				"identifier z used before its declaration",
+				"identifier w used before its declaration",
+			},
+		},
+		{
+			name:  "a function cannot read its own names before declaring them",
+			input: "fn f() { show(x); owo x :=: 1; fn() { y }; owo y :=: 2 }",
+			want:  []string{"identifier x used before its declaration"},
+		},
+		{
+			name:  "anonymous functions can refer to their binding",
+			input: "owo fat :=: fn(n) { if n < 2 { return 1 } return n * fat(n - 1) }",
+			want:  []string{},
+		},
+		{
+			name:  "parameters are scoped to the function",
+			input: "fn f(x) { x }; x",
+			want:  []string{"identifier not found: x"},
+		},
+		{
+			name:  "declarations inside blocks are scoped to the block",
+			input: "if true { owo x :=: 1 } else { owo y :=: 2 }; while(false) { owo z :=: 3 }; x + y + z",
+			want:  []string{"identifier not found: x", "identifier not found: y", "identifier not found: z"},
+		},
+		{
+			name:  "loop variables are scoped to the loop",
+			input: "for(owo i :=: 0; i < 3; i++) { }; i; for v in [1] { v }; v",
+			want:  []string{"identifier not found: i", "identifier not found: v"},
+		},
+		{
+			name:  "destructuring declares every name in the pattern",
+			input: "owo [a, [b], ...c] :=: [1, [2]]; owo {nome, idade: anos = a} :=: {}; a + b + c + nome + anos + idade",
+			want:  []string{"identifier not found: idade"},
+		},
+		{
+			name:  "destructured function values are declared after the value",
+			input: "owo [a] :=: fn() { 1 }; owo {b} :=: fn() { a }; a + b",
+			want:  []string{},
+		},
+		{
+			name:  "tuple destructured function values are declared after the value",
+			input: "owo q, r :=: fn() { 1 }; fn f() { owo s, t :=: fn() { s }; s }; q + r",
+			want:  []string{},
+		},
+		{
+			name:  "defaults are resolved like any expression",
+			input: "owo [a, b = a + limite] :=: [1]",
+			want:  []string{"identifier not found: limite"},
+		},
+		{
+			name:  "parameter patterns are scoped to the function",
+			input: "fn f([x, y], {z}) { x + y + z }; x + z",
+			want:  []string{"identifier not found: x", "identifier not found: z"},
+		},
+		{
+			name:  "named arguments are not references",
+			input: "fn f(x, y = x, ...resto) { x + y + resto }; f(1, y: z)",
+			want:  []string{"identifier not found: z"},
+		},
+		{
+			name:  "spread values are resolved",
+			input: `owo a :=: [1]; f(...a, ...b); [...a, ...c]; {...d, "k": e}`,
+			want:  []string{"identifier not found: f", "identifier not found: b", "identifier not found: c", "identifier not found: d", "identifier not found: e"},
+		},
+		{
+			name:  "both sides of a pipeline are resolved",
+			input: "owo xs :=: [1]; xs |> f(a) |> len",
+			want:  []string{"identifier not found: f", "identifier not found: a"},
+		},
+		{
+			name:  "tuple destructuring declares every name",
+			input: "fn f() { return 1, g, [2] }; owo a, _, [b], ...c :=: f(); a + b + c + _",
+			want:  []string{"identifier not found: g", "identifier not found: _"},
+		},
+		{
+			name:  "match bindings are scoped to their arm",
+			input: "match [1, 2] { [x, ...resto] if x > 0 => resto, {tipo} => tipo, n: int => { owo m :=: n; m } }; x + resto + tipo + n + m",
+			want:  []string{"identifier not found: x", "identifier not found: resto", "identifier not found: tipo", "identifier not found: n", "identifier not found: m"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := New("show", "len")
+			r.Resolve(parse(t, tc.input))
+
+			assert.Equal(t, tc.want, messages(r.Errors()))
+		})
+	}
+}
+
+func TestResolver_BindsToDeclaringScope(t *testing.T) {
+	program := parse(t, `owo x :=: 1; fn f(y) { for(owo i :=: 0; i < y; i++) { x :=: y } }`)
+
+	r := New()
+	r.Resolve(program)
+	assert.Empty(t, r.Errors())
+
+	fn := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	loop := fn.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.ForExpression)
+	bind := loop.Consequence.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.BindExpression)
+
+	assert.Equal(t, 3, bind.Left.(*ast.Identifier).Binding.Depth, "x is global: above the loop body, the loop and the function")
+	assert.Equal(t, 2, bind.Value.(*ast.Identifier).Binding.Depth, "y is a parameter of the function enclosing the loop")
+}
+
+func TestResolver_KeepsGlobalsBetweenPrograms(t *testing.T) {
+	r := New()
+
+	r.Resolve(parse(t, "owo x :=: 1"))
+	assert.Empty(t, r.Errors())
+
+	r.Resolve(parse(t, "owo y :=: naoExiste"))
+	assert.Equal(t, []string{"identifier not found: naoExiste"}, messages(r.Errors()))
+
+	r.Resolve(parse(t, "x + y"))
+	assert.Equal(t, []string{"identifier not found: y"}, messages(r.Errors()), "declarations of a program with errors are discarded")
+}
+
+func TestResolver_ErrorsPointAtTheSource(t *testing.T) {
+	source := "owo x :=: 1\nfn f() {\n  total :=: x + naoExiste\n}"
+
+	r := New()
+	r.Resolve(parse(t, source))
+
+	errors := r.Errors()
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "3:3: error[R002]: cannot assign to undeclared identifier total", errors[0].String())
+	assert.Equal(t, "3:17: error[R001]: identifier not found: naoExiste", errors[1].String())
+	assert.Equal(t, "3:17: error[R001]: identifier not found: naoExiste\n"+
+		"  total :=: x + naoExiste\n"+
+		"                ^^^^^^^^^", errors[1].Render(source))
+}
+
+func TestResolver_ReportsUseBeforeDeclaration(t *testing.T) {
+	r := New("show")
+	r.Resolve(parse(t, "show(y)\nowo y :=: 1"))
+
+	errors := r.Errors()
+	assert.Len(t, errors, 1)
+	assert.Equal(t, CodeUsedBeforeDeclared, errors[0].Code)
+	assert.Equal(t, "1:6: error[R003]: identifier y used before its declaration", errors[0].String())
+	assert.Equal(t, []string{"move the declaration of y above this line"}, errors[0].Suggestions)
+}
@@ -11,17 +11,36 @@ const (
 	INT     = "INT"
 	STRING  = "STRING"
 	FLOAT   = "FLOAT"
+	DECIMAL = "DECIMAL"
 	BOOLEAN = "BOOL"
 
+	// Partes de uma string com interpolação: "head ${x} middle ${y} tail"
+	TEMPLATE_HEAD   = "TEMPLATE_HEAD"
+	TEMPLATE_MIDDLE = "TEMPLATE_MIDDLE"
+	TEMPLATE_TAIL   = "TEMPLATE_TAIL"
+
 	// Operators
-	ASSIGN     = "ASSIGN"
-	PLUS       = "+"
-	PLUSPLUS   = "++"
-	MINUSMINUS = "--"
-	MINUS      = "-"
-	BANG       = "!"
-	ASTERISK   = "*"
-	SLASH      = "/"
+	ASSIGN          = "ASSIGN"
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+	PLUS            = "+"
+	PLUSPLUS        = "++"
+	MINUSMINUS      = "--"
+	MINUS           = "-"
+	BANG            = "!"
+	ASTERISK        = "*"
+	SLASH           = "/"
+	PERCENT         = "%"
+	FLOOR_DIV       = "~/"
+
+	// Bitwise. O ~ é o xor quando fica entre dois valores e o not quando vem antes de um
+	AMPERSAND = "&"
+	PIPE      = "|"
+	TILDE     = "~"
+	SHL       = "<<"
+	SHR       = ">>"
 
 	LT  = "<"
 	GT  = ">"
@@ -32,8 +51,20 @@ const (
 	EQ     = "=="
 	NOT_EQ = "!="
 
+	// x |> f(a) é o mesmo que f(x, a)
+	PIPELINE = "|>"
+
+	// a ?? b só avalia b quando a é null; a?.b e a?[i] dão null em vez de erro quando a é null
+	NULLISH           = "??"
+	OPTIONAL_DOT      = "?."
+	OPTIONAL_LBRACKET = "?["
+
 	// Delimitadores
 	DOT       = "."
+	DOTDOT    = ".."  // Faixa inclusiva num padrão, ex: 1..9
+	ELLIPSIS  = "..." // Resto de um array num padrão, ex: [primeiro, ...resto]
+	ARROW     = "=>"
+	DEFAULT   = "=" // Valor padrão num padrão, ex: [a, b = 0]
 	SEMICOLON = ";"
 	COLON     = ":"
 	COMMA     = ","
@@ -46,34 +77,56 @@ const (
 	RBRACKET = "]"
 
 	// Keywords
-	FN     = "FN"
-	OwO    = "OwO"
-	TRUE   = "TRUE"
-	FALSE  = "FALSE"
-	IF     = "IF"
-	ELSE   = "ELSE"
-	RETURN = "RETURN"
-	WHILE  = "WHILE"
-	AND    = "&&"
-	OR     = "||"
-	FOR    = "FOR"
+	FN       = "FN"
+	OwO      = "OwO"
+	TRUE     = "TRUE"
+	FALSE    = "FALSE"
+	NULL     = "NULL"
+	IF       = "IF"
+	ELSE     = "ELSE"
+	RETURN   = "RETURN"
+	WHILE    = "WHILE"
+	AND      = "&&"
+	OR       = "||"
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	MATCH    = "MATCH"
 )
 
 type Token struct {
 	Type    Type
 	Literal string
+	Pos     Position // Onde o token começa
+	End     Position // Logo depois do último caracter do token
+
+	// Comentários que aparecem logo antes do token. Só é preenchido quando o lexer guarda os comentários
+	Comments []Comment
+}
+
+// Um comentário // ou /* */, com os delimitadores incluídos em Text
+type Comment struct {
+	Text string
+	Pos  Position
+	End  Position
 }
 
 var keywords = map[string]Type{
-	"owo":    OwO,
-	"fn":     FN,
-	"true":   TRUE,
-	"false":  FALSE,
-	"while":  WHILE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"for":    FOR,
+	"owo":      OwO,
+	"fn":       FN,
+	"true":     TRUE,
+	"false":    FALSE,
+	"null":     NULL,
+	"while":    WHILE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"match":    MATCH,
 }
 
 func LookupIdent(identifier string) Type {
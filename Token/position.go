@@ -0,0 +1,75 @@
+package token
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position marca um ponto do código fonte. Line e Column começam em 1; Offset é o índice em bytes
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+// Uma posição sem linha é a posição zero, usada quando o nó ou token não veio do lexer
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// Formata a posição como arquivo:linha:coluna, omitindo o arquivo quando ele não foi informado
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// A linha do código fonte em que pos está, seguida de uma linha com um ^ embaixo da coluna de pos
+func Excerpt(source string, pos Position) string {
+	return ExcerptSpan(source, pos, pos)
+}
+
+// Como Excerpt, mas sublinha com ^ o trecho de start até end
+func ExcerptSpan(source string, start, end Position) string {
+	if !start.IsValid() {
+		return ""
+	}
+
+	lines := strings.Split(source, "\n")
+	if start.Line > len(lines) {
+		return ""
+	}
+	line := strings.TrimRight(lines[start.Line-1], "\r")
+	if start.Line == 1 {
+		line = strings.TrimPrefix(line, "\uFEFF")
+	}
+	// As colunas contam caracteres, não bytes
+	chars := []rune(line)
+
+	var caret strings.Builder
+	for i := 0; i < start.Column-1 && i < len(chars); i++ {
+		// Mantém os tabs para que o ^ fique alinhado com a linha de cima
+		if chars[i] == '\t' {
+			caret.WriteByte('\t')
+		} else {
+			caret.WriteByte(' ')
+		}
+	}
+	caret.WriteByte('^')
+
+	// Um trecho que continua nas linhas seguintes é sublinhado até o fim da primeira linha
+	last := len(chars) + 1
+	if end.Line == start.Line {
+		last = end.Column
+	} else if end.Line < start.Line {
+		last = start.Column
+	}
+	for i := start.Column + 1; i < last && i <= len(chars); i++ {
+		caret.WriteByte('^')
+	}
+
+	return line + "\n" + caret.String()
+}
@@ -7,12 +7,14 @@ import (
 	lexer "github.com/ZooeyLang/Lexer"
 	object "github.com/ZooeyLang/Object"
 	parser "github.com/ZooeyLang/Parser"
+	resolver "github.com/ZooeyLang/Resolver"
 )
 
 func main() {
 
 	env := object.NewEnvironment()
-	l := lexer.New(`
+	source := `
+	// Aprovado só com nota acima de 7
 	fn passouDeAno(nota){
 		if nota > 7 {
 			return true
@@ -44,17 +46,35 @@ func main() {
 	}
 	valorizador()
 	show(testeEscopo)
-	`)
+	`
+	l := lexer.NewFile("main.zooey", source)
 	p := parser.New(l)
 
 	program := p.ParseProgram()
 
 	if len(p.Errors()) != 0 {
-		fmt.Println(p.Errors())
+		for _, d := range p.Errors() {
+			fmt.Println(d.Render(source))
+		}
+		return
+	}
+
+	r := resolver.New(evaluator.BuiltinNames()...)
+	r.Resolve(program)
+
+	if len(r.Errors()) != 0 {
+		for _, d := range r.Errors() {
+			fmt.Println(d.Render(source))
+		}
+		return
 	}
 
 	evaluated := evaluator.Eval(program, env)
+	if err, ok := evaluated.(*object.Error); ok {
+		fmt.Println(err.Report(source))
+		return
+	}
 	if evaluated != nil {
-		fmt.Println(evaluated)
+		fmt.Println(evaluated.Inspect())
 	}
 }